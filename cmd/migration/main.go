@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/capigiba/capiary/internal/config"
+	"github.com/capigiba/capiary/internal/infra/db/migration"
+	"github.com/capigiba/capiary/internal/infra/db/mongodb"
+	"github.com/capigiba/capiary/internal/infra/db/postgres"
+	"github.com/capigiba/capiary/internal/repositories"
+	"github.com/capigiba/capiary/pkg/logger"
+)
+
+const usage = `Usage: migration [flags] <command>
+
+Commands:
+  status   list migrations and whether they have been applied
+  up       apply every pending migration
+  down     roll back the most recently applied migrations (see -steps)
+  redo     roll back the most recently applied migration and apply it again
+
+Flags:
+`
+
+func main() {
+	appLogger := logger.NewLogger("Migration")
+
+	dryRun := flag.Bool("dry-run", false, "print what would run without changing anything")
+	steps := flag.Int("steps", 1, "number of migrations down rolls back")
+	only := flag.String("source", "", `limit to one database: "postgres" or "mongodb"`)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		appLogger.Errorf("config loading error: %w", err)
+		os.Exit(1)
+	}
+
+	var sources []migration.Source
+	if *only == "" || *only == "postgres" {
+		db, err := postgres.NewPostgresDB(cfg.Database.RdsPostgresURL)
+		if err != nil {
+			appLogger.Errorf("database initialization error: %w", err)
+			os.Exit(1)
+		}
+		src, err := migration.NewPostgresSource(db)
+		if err != nil {
+			appLogger.Errorf("failed to load postgres migrations: %w", err)
+			os.Exit(1)
+		}
+		sources = append(sources, src)
+	}
+	if *only == "" || *only == "mongodb" {
+		dbMongoConn := mongodb.NewMongoDBClient(cfg.Database.MongodbURI)
+		indexed := []repositories.IndexedRepository{
+			repositories.NewCategoryRepository(dbMongoConn),
+			repositories.NewBlogPostRepository(dbMongoConn),
+			repositories.NewBlogRevisionRepository(dbMongoConn),
+		}
+		db := dbMongoConn.GetClient().Database("capiary")
+		sources = append(sources, migration.NewMongoSource(db, mongoMigrations(indexed)...))
+	}
+	if len(sources) == 0 {
+		appLogger.Errorf("unknown source %q", *only)
+		os.Exit(2)
+	}
+
+	runner := migration.NewRunner(os.Stdout, *dryRun, sources...)
+	switch cmd := flag.Arg(0); cmd {
+	case "status":
+		err = runner.Status(ctx)
+	case "up":
+		err = runner.Up(ctx)
+	case "down":
+		err = runner.Down(ctx, *steps)
+	case "redo":
+		err = runner.Redo(ctx)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		appLogger.Errorf("migration failed: %w", err)
+		os.Exit(1)
+	}
+}
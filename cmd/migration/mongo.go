@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/infra/db/migration"
+	"github.com/capigiba/capiary/internal/repositories"
+	"github.com/capigiba/capiary/pkg/slug"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoMigrations brings blog and category documents written by older builds
+// up to the current shape, then locks that shape in with validators and
+// indexes. Migrations keep their own copy of any logic they need, so later
+// changes to the services cannot change what an old migration does.
+func mongoMigrations(indexed []repositories.IndexedRepository) []migration.MongoMigration {
+	return []migration.MongoMigration{
+		{
+			Version:     20261017000100,
+			Name:        "snake_case_fields",
+			Description: "rename authorid, createdat and updatedat, written before fields had bson tags, to their snake_case names",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return renameFields(ctx, db, legacyFieldNames)
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				reversed := make(map[string]map[string]string, len(legacyFieldNames))
+				for collection, names := range legacyFieldNames {
+					reversed[collection] = make(map[string]string, len(names))
+					for from, to := range names {
+						reversed[collection][to] = from
+					}
+				}
+				return renameFields(ctx, db, reversed)
+			},
+		},
+		{
+			Version:     20261017000200,
+			Name:        "backfill_posts",
+			Description: "convert hex category references to ObjectIDs, default version and editor_ids, and give every post a unique slug",
+			Up:          backfillPosts,
+		},
+		{
+			Version:     20261017000300,
+			Name:        "backfill_categories",
+			Description: "default status to active and ancestors to [], and derive normalized_name for live categories",
+			Up:          backfillCategories,
+		},
+		{
+			Version:     20261017000400,
+			Name:        "collection_validators",
+			Description: "attach $jsonSchema validators to blog and category (moderate level, so legacy documents stay editable)",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				for collection, schema := range validators() {
+					if err := setValidator(ctx, db, collection, schema); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				for collection := range validators() {
+					if err := setValidator(ctx, db, collection, bson.M{}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     20261017000500,
+			Name:        "declared_indexes",
+			Description: "create the indexes repositories declare, which the unique ones need the backfills above for",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				for _, repo := range indexed {
+					if _, err := repo.EnsureIndexes(ctx); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     20261017000600,
+			Name:        "nullable_parent_id",
+			Description: "let category parent_id be null, which is how categories moved to the root are stored",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return setValidator(ctx, db, "category", categoryValidatorNullableParent())
+			},
+			Down: func(ctx context.Context, db *mongo.Database) error {
+				return setValidator(ctx, db, "category", validators()["category"])
+			},
+		},
+	}
+}
+
+var legacyFieldNames = map[string]map[string]string{
+	"blog":     {"authorid": "author_id", "createdat": "created_at", "updatedat": "updated_at"},
+	"category": {"createdat": "created_at", "updatedat": "updated_at"},
+}
+
+func renameFields(ctx context.Context, db *mongo.Database, names map[string]map[string]string) error {
+	for collection, renames := range names {
+		for from, to := range renames {
+			_, err := db.Collection(collection).UpdateMany(ctx,
+				bson.M{from: bson.M{"$exists": true}},
+				bson.M{"$rename": bson.M{from: to}})
+			if err != nil {
+				return fmt.Errorf("failed to rename %s.%s: %w", collection, from, err)
+			}
+		}
+	}
+	return nil
+}
+
+func backfillPosts(ctx context.Context, db *mongo.Database) error {
+	blog := db.Collection("blog")
+
+	// Categories used to be stored as hex strings. Anything that is not a
+	// valid ObjectID is kept as is rather than failing the whole migration.
+	_, err := blog.UpdateMany(ctx,
+		bson.M{"categories": bson.M{"$type": "string"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"categories": bson.M{"$map": bson.M{
+			"input": "$categories",
+			"in": bson.M{"$convert": bson.M{
+				"input":   "$$this",
+				"to":      "objectId",
+				"onError": "$$this",
+			}},
+		}}}}}})
+	if err != nil {
+		return fmt.Errorf("failed to convert post categories: %w", err)
+	}
+
+	// Optimistic concurrency matches on version, which a missing field never equals.
+	for field, value := range map[string]interface{}{"version": int64(0), "editor_ids": bson.A{}} {
+		if _, err := blog.UpdateMany(ctx, bson.M{field: bson.M{"$exists": false}}, bson.M{"$set": bson.M{field: value}}); err != nil {
+			return fmt.Errorf("failed to default %s: %w", field, err)
+		}
+	}
+
+	return backfillSlugs(ctx, blog)
+}
+
+// backfillSlugs gives slug-less posts one derived from the title, suffixed
+// with -2, -3, ... on collisions, as the blog service does for new posts.
+func backfillSlugs(ctx context.Context, blog *mongo.Collection) error {
+	taken := make(map[string]bool)
+	values, err := blog.Distinct(ctx, "slug", bson.M{"slug": bson.M{"$gt": ""}})
+	if err != nil {
+		return fmt.Errorf("failed to load slugs: %w", err)
+	}
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			taken[s] = true
+		}
+	}
+
+	cursor, err := blog.Find(ctx,
+		bson.M{"$or": bson.A{bson.M{"slug": bson.M{"$exists": false}}, bson.M{"slug": ""}}},
+		options.Find().SetProjection(bson.M{"title": 1}).SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("failed to find posts without slugs: %w", err)
+	}
+	var posts []struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Title string             `bson:"title"`
+	}
+	if err := cursor.All(ctx, &posts); err != nil {
+		return fmt.Errorf("failed to decode posts: %w", err)
+	}
+
+	for _, post := range posts {
+		base := slug.Make(post.Title)
+		if base == "" {
+			base = "post"
+		}
+		candidate := base
+		for n := 2; taken[candidate]; n++ {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken[candidate] = true
+		if _, err := blog.UpdateOne(ctx, bson.M{"_id": post.ID}, bson.M{"$set": bson.M{"slug": candidate}}); err != nil {
+			return fmt.Errorf("failed to set slug on %s: %w", post.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func backfillCategories(ctx context.Context, db *mongo.Database) error {
+	category := db.Collection("category")
+
+	defaults := map[string]interface{}{
+		"status":    constant.CategoryStatusActive,
+		"ancestors": bson.A{},
+	}
+	for field, value := range defaults {
+		if _, err := category.UpdateMany(ctx, bson.M{field: bson.M{"$exists": false}}, bson.M{"$set": bson.M{field: value}}); err != nil {
+			return fmt.Errorf("failed to default %s: %w", field, err)
+		}
+	}
+
+	cursor, err := category.Find(ctx,
+		bson.M{"status": bson.M{"$ne": constant.CategoryStatusDeleted}},
+		options.Find().SetProjection(bson.M{"name": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to load categories: %w", err)
+	}
+	var categories []struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Name string             `bson:"name"`
+	}
+	if err := cursor.All(ctx, &categories); err != nil {
+		return fmt.Errorf("failed to decode categories: %w", err)
+	}
+
+	// The unique index on normalized_name would reject duplicates later, so
+	// report them all now, before any name is written.
+	owners := make(map[string][]string)
+	for _, c := range categories {
+		key := strings.ToLower(strings.Join(strings.Fields(c.Name), " "))
+		owners[key] = append(owners[key], c.ID.Hex())
+	}
+	var clashes []string
+	for key, ids := range owners {
+		if len(ids) > 1 {
+			clashes = append(clashes, fmt.Sprintf("%q (%s)", key, strings.Join(ids, ", ")))
+		}
+	}
+	if len(clashes) > 0 {
+		sort.Strings(clashes)
+		return fmt.Errorf("categories share a name; rename or merge them first: %s", strings.Join(clashes, "; "))
+	}
+
+	for _, c := range categories {
+		key := strings.ToLower(strings.Join(strings.Fields(c.Name), " "))
+		if _, err := category.UpdateOne(ctx, bson.M{"_id": c.ID}, bson.M{"$set": bson.M{"normalized_name": key}}); err != nil {
+			return fmt.Errorf("failed to set normalized_name on %s: %w", c.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func validators() map[string]bson.M {
+	blogStatuses := bson.A{}
+	for _, s := range constant.AllBlogStatus {
+		blogStatuses = append(blogStatuses, string(s))
+	}
+	categoryStatuses := bson.A{}
+	for _, s := range constant.AllCategoryStatus {
+		categoryStatuses = append(categoryStatuses, string(s))
+	}
+	integer := bson.A{"int", "long"}
+
+	return map[string]bson.M{
+		"blog": {
+			"bsonType": "object",
+			"required": bson.A{"title", "status", "author_id", "created_at"},
+			"properties": bson.M{
+				"title":      bson.M{"bsonType": "string"},
+				"slug":       bson.M{"bsonType": "string"},
+				"status":     bson.M{"enum": blogStatuses},
+				"author_id":  bson.M{"bsonType": integer},
+				"editor_ids": bson.M{"bsonType": "array", "items": bson.M{"bsonType": integer}},
+				"categories": bson.M{"bsonType": "array", "items": bson.M{"bsonType": "objectId"}},
+				"blocks":     bson.M{"bsonType": "array"},
+				"version":    bson.M{"bsonType": integer},
+				"created_at": bson.M{"bsonType": "date"},
+				"updated_at": bson.M{"bsonType": "date"},
+			},
+		},
+		"category": {
+			"bsonType": "object",
+			"required": bson.A{"name", "status", "created_at"},
+			"properties": bson.M{
+				"name":            bson.M{"bsonType": "string", "minLength": 1},
+				"status":          bson.M{"enum": categoryStatuses},
+				"normalized_name": bson.M{"bsonType": bson.A{"string", "null"}},
+				"parent_id":       bson.M{"bsonType": "objectId"},
+				"ancestors":       bson.M{"bsonType": "array", "items": bson.M{"bsonType": "objectId"}},
+				"created_at":      bson.M{"bsonType": "date"},
+				"updated_at":      bson.M{"bsonType": "date"},
+			},
+		},
+	}
+}
+
+// categoryValidatorNullableParent is the category schema of
+// collection_validators with parent_id also allowed to be null.
+func categoryValidatorNullableParent() bson.M {
+	schema := validators()["category"]
+	properties := bson.M{}
+	for name, property := range schema["properties"].(bson.M) {
+		properties[name] = property
+	}
+	properties["parent_id"] = bson.M{"bsonType": bson.A{"objectId", "null"}}
+
+	out := bson.M{}
+	for key, value := range schema {
+		out[key] = value
+	}
+	out["properties"] = properties
+	return out
+}
+
+// setValidator installs a $jsonSchema validator, creating the collection if
+// it does not exist yet. An empty schema removes validation.
+func setValidator(ctx context.Context, db *mongo.Database, collection string, schema bson.M) error {
+	validator := bson.M{}
+	if len(schema) > 0 {
+		validator = bson.M{"$jsonSchema": schema}
+	}
+
+	names, err := db.ListCollectionNames(ctx, bson.M{"name": collection})
+	if err != nil {
+		return fmt.Errorf("failed to look up %s: %w", collection, err)
+	}
+	if len(names) == 0 {
+		opts := options.CreateCollection().SetValidator(validator).SetValidationLevel("moderate")
+		if err := db.CreateCollection(ctx, collection, opts); err != nil {
+			return fmt.Errorf("failed to create %s: %w", collection, err)
+		}
+		return nil
+	}
+
+	err = db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "validator", Value: validator},
+		{Key: "validationLevel", Value: "moderate"},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to set validator on %s: %w", collection, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// bsonTypeOf names the BSON type a Go value is stored as, for the types the
+// services write.
+func bsonTypeOf(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case *primitive.ObjectID:
+		if x == nil {
+			return "null"
+		}
+		return "objectId"
+	case primitive.ObjectID:
+		return "objectId"
+	case string:
+		return "string"
+	case time.Time:
+		return "date"
+	case []primitive.ObjectID, bson.A:
+		return "array"
+	}
+	return "unknown"
+}
+
+// violations lists the fields of doc whose type the schema's properties do
+// not allow. Only bsonType is checked.
+func violations(schema bson.M, doc bson.M) []string {
+	var bad []string
+	properties := schema["properties"].(bson.M)
+	for field, value := range doc {
+		property, ok := properties[field].(bson.M)
+		if !ok || property["bsonType"] == nil {
+			continue
+		}
+		allowed := bson.A{property["bsonType"]}
+		if list, ok := property["bsonType"].(bson.A); ok {
+			allowed = list
+		}
+		found := false
+		for _, t := range allowed {
+			if t == bsonTypeOf(value) {
+				found = true
+			}
+		}
+		if !found {
+			bad = append(bad, field)
+		}
+	}
+	return bad
+}
+
+func TestCategoryValidatorAcceptsMoves(t *testing.T) {
+	parent := primitive.NewObjectID()
+	var root *primitive.ObjectID
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		doc  bson.M
+	}{
+		// The fields moveCategory sets.
+		{"move under a parent", bson.M{"parent_id": &parent, "ancestors": []primitive.ObjectID{parent}, "updated_at": now}},
+		{"move to the root", bson.M{"parent_id": root, "ancestors": []primitive.ObjectID{}, "updated_at": now}},
+		{"deleted category", bson.M{"normalized_name": nil, "status": "deleted"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if bad := violations(categoryValidatorNullableParent(), tc.doc); len(bad) > 0 {
+				t.Errorf("fields %v fail validation", bad)
+			}
+		})
+	}
+
+	if bad := violations(validators()["category"], bson.M{"parent_id": root}); len(bad) != 1 {
+		t.Errorf("collection_validators should still reject a null parent_id, got violations %v", bad)
+	}
+}
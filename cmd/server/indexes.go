@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/capigiba/capiary/internal/repositories"
+	"github.com/capigiba/capiary/pkg/logger"
+)
+
+// syncIndexes reconciles every repository's declared MongoDB indexes and logs
+// whatever had to change.
+func syncIndexes(ctx context.Context, log logger.Logger, repos []repositories.IndexedRepository) error {
+	for _, repo := range repos {
+		diff, err := repo.EnsureIndexes(ctx)
+		if err != nil {
+			return err
+		}
+		for _, spec := range diff.Missing {
+			log.Infof("created index %s on %s", spec.Name, diff.Collection)
+		}
+		for _, change := range diff.Changed {
+			log.Infof("rebuilt index %s on %s", change.Name, diff.Collection)
+		}
+		for _, name := range diff.Extra {
+			log.Warnf("index %s on %s is not declared by any repository", name, diff.Collection)
+		}
+	}
+	return nil
+}
+
+// printIndexDiff backs the "indexes" subcommand: it prints how the database
+// differs from the declared indexes without changing anything.
+//
+//	go run ./cmd/server indexes
+func printIndexDiff(ctx context.Context, repos []repositories.IndexedRepository) error {
+	for _, repo := range repos {
+		diff, err := repo.DiffIndexes(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stdout, diff.String())
+	}
+	return nil
+}
@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/capigiba/capiary/internal/config"
 	handler "github.com/capigiba/capiary/internal/handler/rest/v1"
 	"github.com/capigiba/capiary/internal/infra/db/mongodb"
 	"github.com/capigiba/capiary/internal/infra/db/postgres"
+	"github.com/capigiba/capiary/internal/infra/search"
 	"github.com/capigiba/capiary/internal/infra/storage"
 	"github.com/capigiba/capiary/internal/middleware"
 	"github.com/capigiba/capiary/internal/repositories"
 	"github.com/capigiba/capiary/internal/router"
 	"github.com/capigiba/capiary/internal/services"
+	"github.com/capigiba/capiary/internal/worker"
 	"github.com/capigiba/capiary/pkg/logger"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -20,20 +28,41 @@ import (
 func main() {
 	appLogger := logger.NewLogger("Initialize")
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		appLogger.Errorf("config loading error: %w", err)
 		os.Exit(1)
 	}
 
+	dbMongoConn := mongodb.NewMongoDBClient(cfg.Database.MongodbURI)
+
+	categoryRepo := repositories.NewCategoryRepository(dbMongoConn)
+	blogRepo := repositories.NewBlogPostRepository(dbMongoConn)
+	blogRevisionRepo := repositories.NewBlogRevisionRepository(dbMongoConn)
+	indexedRepos := []repositories.IndexedRepository{categoryRepo, blogRepo, blogRevisionRepo}
+
+	if len(os.Args) > 1 && os.Args[1] == "indexes" {
+		if err := printIndexDiff(ctx, indexedRepos); err != nil {
+			appLogger.Errorf("index diff error: %w", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := syncIndexes(ctx, appLogger, indexedRepos); err != nil {
+		appLogger.Errorf("index reconciliation error: %w", err)
+		os.Exit(1)
+	}
+
 	dbPostgresConn, err := postgres.NewPostgresDB(cfg.Database.RdsPostgresURL)
 	if err != nil {
 		appLogger.Errorf("database initialization error: %w", err)
 		os.Exit(1)
 	}
 
-	dbMongoConn := mongodb.NewMongoDBClient(cfg.Database.MongodbURI)
-
 	storageClient, err := storage.NewS3Uploader(
 		cfg.Storage.AwsAccessKeyID,
 		cfg.Storage.AwsSecretKey,
@@ -46,15 +75,13 @@ func main() {
 
 	userRepo := repositories.NewUserRepo(dbPostgresConn)
 	authUserMiddleware := middleware.NewAuthUserMiddleware(userRepo, cfg.Server.JWTSecret)
-	userService := services.NewUserService(userRepo, authUserMiddleware)
+	userService := services.NewUserService(userRepo, repositories.NewUserAuditRepo(dbPostgresConn), repositories.NewTransactor(dbPostgresConn), authUserMiddleware)
 	userHandler := handler.NewUserHandler(userService)
 
-	blogRepo := repositories.NewBlogPostRepository(dbMongoConn)
-	blogService := services.NewBlogPostService(blogRepo, storageClient)
+	blogService := services.NewBlogPostService(blogRepo, blogRevisionRepo, categoryRepo, search.NewMongoPostSearcher(dbMongoConn), storageClient)
 	blogHandler := handler.NewBlogPostHandler(blogService)
 
-	categoryRepo := repositories.NewCategoryRepository(dbMongoConn)
-	categoryService := services.NewCategoryService(categoryRepo)
+	categoryService := services.NewCategoryService(categoryRepo, blogRepo)
 	categoryHandler := handler.NewCategoryHandler(categoryService)
 
 	swaggerRouter := router.NewSwaggerRouter()
@@ -84,11 +111,34 @@ func main() {
 	registerAPIRoutes(apiGroup, appRouter)
 	registerSwaggerRoutes(router, appRouter)
 
+	publishScheduler := worker.NewPublishScheduler(blogService, cfg.Scheduler.PublishInterval)
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		publishScheduler.Run(ctx)
+	}()
+
 	port := cfg.Server.Port
-	if err := router.Run(":" + port); err != nil {
-		appLogger.Errorf("Failed to start the server: %v", err)
-		os.Exit(1)
+	server := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			appLogger.Errorf("Failed to start the server: %v", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	appLogger.Info("Shutting down the server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		appLogger.Errorf("Server forced to shutdown: %v", err)
 	}
+	<-schedulerDone
 }
 
 func registerAPIRoutes(group *gin.RouterGroup, appRouter *router.AppRouter) {
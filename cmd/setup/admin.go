@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/repositories"
+	"github.com/capigiba/capiary/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minAdminPasswordLength guards against bootstrapping an admin with a trivial password.
+const minAdminPasswordLength = 12
+
+// setupActorID is the actor recorded in the user audit log for changes setup
+// makes, since no admin is signed in to make them.
+const setupActorID = 0
+
+// ensureAdmin creates the first administrator, or makes sure an existing
+// account with that email is an active admin. An existing password is never
+// changed, so the password is only needed on the first run. Promoting an
+// existing account is audited like an admin's role or status change.
+func ensureAdmin(ctx context.Context, log logger.Logger, users repositories.UserRepository, audit repositories.UserAuditRepository, tx repositories.Transactor, seed seedAdmin) (*entity.User, error) {
+	if seed.Email == "" {
+		return nil, fmt.Errorf("SETUP_ADMIN_EMAIL is not set")
+	}
+
+	existing, err := users.GetUserByEmail(ctx, seed.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", seed.Email, err)
+	}
+	if existing != nil {
+		if existing.Role == constant.RoleAdmin && existing.Status == constant.StatusActive {
+			log.Infof("admin %s already exists", seed.Email)
+			return existing, nil
+		}
+		if err := promoteAdmin(ctx, users, audit, tx, existing); err != nil {
+			return nil, fmt.Errorf("failed to promote %s: %w", seed.Email, err)
+		}
+		log.Infof("promoted existing user %s to active admin", seed.Email)
+		return existing, nil
+	}
+
+	if len(seed.Password) < minAdminPasswordLength {
+		return nil, fmt.Errorf("SETUP_ADMIN_PASSWORD must be at least %d characters", minAdminPasswordLength)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(seed.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	admin := &entity.User{
+		FirstName: seed.FirstName,
+		LastName:  seed.LastName,
+		UserName:  seed.Username,
+		Email:     seed.Email,
+		Password:  string(hashed),
+		Status:    constant.StatusActive,
+		Role:      constant.RoleAdmin,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := users.CreateUser(ctx, admin); err != nil {
+		return nil, fmt.Errorf("failed to create admin %s: %w", seed.Email, err)
+	}
+	log.Infof("created admin %s", seed.Email)
+	return admin, nil
+}
+
+// promoteAdmin makes user an active admin and records each change in the user
+// audit log, in one transaction.
+func promoteAdmin(ctx context.Context, users repositories.UserRepository, audit repositories.UserAuditRepository, tx repositories.Transactor, user *entity.User) error {
+	now := time.Now()
+	var entries []entity.UserAuditEntry
+	if user.Role != constant.RoleAdmin {
+		entries = append(entries, entity.UserAuditEntry{
+			Action: constant.UserAuditChangeRole,
+			From:   string(user.Role),
+			To:     string(constant.RoleAdmin),
+		})
+	}
+	if user.Status != constant.StatusActive {
+		action := constant.UserAuditReinstate
+		if user.Status == constant.StatusPending {
+			action = constant.UserAuditApprove
+		}
+		entries = append(entries, entity.UserAuditEntry{
+			Action: action,
+			From:   string(user.Status),
+			To:     string(constant.StatusActive),
+		})
+	}
+
+	user.Role = constant.RoleAdmin
+	user.Status = constant.StatusActive
+	user.StatusReason = ""
+	user.UpdatedAt = now
+	return tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := users.UpdateUser(ctx, user); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			entry.ActorID = setupActorID
+			entry.TargetID = user.ID
+			entry.Reason = "promoted by setup"
+			entry.CreatedAt = now
+			if err := audit.Add(ctx, &entry); err != nil {
+				return fmt.Errorf("failed to audit %s: %w", entry.Action, err)
+			}
+		}
+		return nil
+	})
+}
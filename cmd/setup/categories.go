@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/repositories"
+	"github.com/capigiba/capiary/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// seedCategories creates the categories from the seed file that do not exist
+// yet, matching by normalized name like the unique index does. Existing ones
+// are left exactly as they are, even if they were moved or renamed since.
+// It returns every live category keyed by normalized name.
+func seedCategories(ctx context.Context, log logger.Logger, repo repositories.CategoryRepository, seed []seedCategory) (map[string]entity.Category, error) {
+	all, err := repo.LoadAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load categories: %w", err)
+	}
+	byName := make(map[string]entity.Category, len(all))
+	for _, c := range all {
+		byName[entity.NormalizeCategoryName(c.Name)] = c
+	}
+
+	if err := createCategories(ctx, log, repo, seed, nil, byName); err != nil {
+		return nil, err
+	}
+	return byName, nil
+}
+
+func createCategories(ctx context.Context, log logger.Logger, repo repositories.CategoryRepository, seed []seedCategory, parent *entity.Category, byName map[string]entity.Category) error {
+	for _, node := range seed {
+		name := strings.TrimSpace(node.Name)
+		key := entity.NormalizeCategoryName(name)
+		if key == "" {
+			return fmt.Errorf("seed category without a name")
+		}
+		for _, role := range node.Access {
+			if !constant.IsValidRole(role) {
+				return fmt.Errorf("category %q: unknown role %q", name, role)
+			}
+		}
+
+		category, ok := byName[key]
+		if ok {
+			log.Infof("category %q already exists", name)
+		} else {
+			now := time.Now()
+			category = entity.Category{
+				ID:             primitive.NewObjectID(),
+				Name:           name,
+				NormalizedName: key,
+				Description:    node.Description,
+				Access:         node.Access,
+				Status:         constant.CategoryStatusActive,
+				Ancestors:      []primitive.ObjectID{},
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			if parent != nil {
+				category.ParentID = &parent.ID
+				category.Ancestors = append(append([]primitive.ObjectID{}, parent.Ancestors...), parent.ID)
+			}
+			if _, err := repo.Add(ctx, category); err != nil {
+				return fmt.Errorf("failed to create category %q: %w", name, err)
+			}
+			byName[key] = category
+			log.Infof("created category %q", name)
+		}
+
+		if err := createCategories(ctx, log, repo, node.Children, &category, byName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
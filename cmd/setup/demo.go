@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/infra/storage"
+	"github.com/capigiba/capiary/internal/repositories"
+	"github.com/capigiba/capiary/pkg/logger"
+	"github.com/capigiba/capiary/pkg/slug"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// demoLoader publishes the seed file's demo posts as the admin, uploading
+// their media to the configured storage. A post whose slug already exists is
+// skipped before anything is uploaded, so reruns neither duplicate posts nor
+// leave stray files behind. A post is only kept once its first revision is
+// recorded.
+type demoLoader struct {
+	log        logger.Logger
+	posts      repositories.BlogPostRepository
+	revisions  repositories.BlogRevisionRepository
+	uploader   storage.S3UploaderInterface
+	mediaDir   string
+	author     *entity.User
+	categories map[string]entity.Category
+}
+
+func (d *demoLoader) load(ctx context.Context, seed []seedPost) error {
+	for _, sp := range seed {
+		postSlug := slug.Make(sp.Title)
+		if postSlug == "" {
+			return fmt.Errorf("demo post %q has no usable title", sp.Title)
+		}
+		existing, err := d.posts.FindOneByQuery(ctx, bson.M{"slug": postSlug})
+		if err != nil {
+			return fmt.Errorf("failed to look up post %q: %w", sp.Title, err)
+		}
+		if existing != nil {
+			d.log.Infof("demo post %q already exists", sp.Title)
+			continue
+		}
+
+		post, err := d.build(sp, postSlug)
+		if err != nil {
+			return err
+		}
+		if _, err := d.posts.Add(ctx, post); err != nil {
+			return fmt.Errorf("failed to insert demo post %q: %w", sp.Title, err)
+		}
+
+		revision := entity.BlogRevision{
+			ID:        primitive.NewObjectID(),
+			PostID:    post.ID,
+			AuthorID:  d.author.ID,
+			Title:     post.Title,
+			Blocks:    post.Blocks,
+			CreatedAt: post.CreatedAt,
+		}
+		if _, err := d.revisions.Add(ctx, revision); err != nil {
+			// Remove the post again, or the next run would skip it by its
+			// slug and it would never get a revision.
+			if deleteErr := d.posts.DeleteByID(ctx, post.ID); deleteErr != nil {
+				return fmt.Errorf("failed to record revision of %q: %w; the post was kept because deleting it failed: %v", sp.Title, err, deleteErr)
+			}
+			return fmt.Errorf("failed to record revision of %q: %w", sp.Title, err)
+		}
+		d.log.Infof("published demo post %q", sp.Title)
+	}
+	return nil
+}
+
+func (d *demoLoader) build(sp seedPost, postSlug string) (entity.BlogPost, error) {
+	now := time.Now()
+	post := entity.BlogPost{
+		ID:          primitive.NewObjectID(),
+		AuthorID:    d.author.ID,
+		EditorIDs:   []uint64{},
+		Categories:  []primitive.ObjectID{},
+		Title:       sp.Title,
+		Slug:        postSlug,
+		Status:      constant.BlogStatusPublished,
+		Version:     1,
+		PublishedAt: &now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	for _, name := range sp.Categories {
+		category, ok := d.categories[entity.NormalizeCategoryName(name)]
+		if !ok {
+			return post, fmt.Errorf("demo post %q: unknown category %q", sp.Title, name)
+		}
+		post.Categories = append(post.Categories, category.ID)
+	}
+
+	for i, sb := range sp.Blocks {
+		block := entity.Block{ID: i + 1, Order: i}
+		switch {
+		case sb.Heading != "":
+			block.Type = entity.BlockTypeHeading
+			block.Heading = &entity.HeadingBlock{Level: 2, Text: sb.Heading}
+		case sb.Text != "":
+			block.Type = entity.BlockTypeText
+			block.Text = &entity.TextBlock{Paragraphs: []entity.Paragraph{
+				{ID: 1, Text: sb.Text, Formats: []entity.Format{}, Align: "left"},
+			}}
+		case sb.Image != "":
+			key, err := d.upload(sb.Image)
+			if err != nil {
+				return post, fmt.Errorf("demo post %q: %w", sp.Title, err)
+			}
+			block.Type = entity.BlockTypeImage
+			block.Image = &entity.ImageBlock{ID: i + 1, Filename: key}
+		default:
+			return post, fmt.Errorf("demo post %q: block %d is empty", sp.Title, i)
+		}
+		post.Blocks = append(post.Blocks, block)
+	}
+	return post, nil
+}
+
+func (d *demoLoader) upload(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(d.mediaDir, path))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	fileType := mime.TypeByExtension(filepath.Ext(path))
+	if fileType == "" {
+		fileType = "application/octet-stream"
+	}
+	key, err := d.uploader.UploadFile(constant.S3FolderImage, filepath.Base(path), fileType, strconv.FormatUint(d.author.ID, 10), data)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s: %w", path, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/capigiba/capiary/internal/config"
+	"github.com/capigiba/capiary/internal/infra/db/mongodb"
+	"github.com/capigiba/capiary/internal/infra/db/postgres"
+	"github.com/capigiba/capiary/internal/infra/storage"
+	"github.com/capigiba/capiary/internal/repositories"
+	"github.com/capigiba/capiary/pkg/logger"
+)
+
+// setup bootstraps a fresh install: it creates the first admin, who can then
+// approve everyone else, and seeds reference data. Every step only adds what
+// is missing, so it is safe to run again. Run the migrations first.
+//
+//	SETUP_ADMIN_EMAIL=... SETUP_ADMIN_PASSWORD=... go run ./cmd/setup [-demo]
+func main() {
+	appLogger := logger.NewLogger("Setup")
+
+	seedPath := flag.String("file", "seed/seed.yaml", "seed file with the admin profile, categories and demo posts")
+	demo := flag.Bool("demo", false, "also publish the demo posts and upload their media")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		appLogger.Errorf("config loading error: %w", err)
+		os.Exit(1)
+	}
+	seed, err := loadSeed(*seedPath)
+	if err != nil {
+		appLogger.Errorf("seed loading error: %w", err)
+		os.Exit(1)
+	}
+
+	dbPostgresConn, err := postgres.NewPostgresDB(cfg.Database.RdsPostgresURL)
+	if err != nil {
+		appLogger.Errorf("database initialization error: %w", err)
+		os.Exit(1)
+	}
+	admin, err := ensureAdmin(ctx, appLogger,
+		repositories.NewUserRepo(dbPostgresConn),
+		repositories.NewUserAuditRepo(dbPostgresConn),
+		repositories.NewTransactor(dbPostgresConn),
+		seed.Admin)
+	if err != nil {
+		appLogger.Errorf("admin setup failed: %w", err)
+		os.Exit(1)
+	}
+
+	dbMongoConn := mongodb.NewMongoDBClient(cfg.Database.MongodbURI)
+	categories, err := seedCategories(ctx, appLogger, repositories.NewCategoryRepository(dbMongoConn), seed.Categories)
+	if err != nil {
+		appLogger.Errorf("category seeding failed: %w", err)
+		os.Exit(1)
+	}
+
+	if !*demo {
+		return
+	}
+	uploader, err := storage.NewS3Uploader(
+		cfg.Storage.AwsAccessKeyID,
+		cfg.Storage.AwsSecretKey,
+		cfg.Storage.AwsRegion,
+		cfg.Storage.AwsBucket,
+	)
+	if err != nil {
+		appLogger.Errorf("Failed to initialize AWS S3 client: %v", err)
+		os.Exit(1)
+	}
+	loader := &demoLoader{
+		log:        appLogger,
+		posts:      repositories.NewBlogPostRepository(dbMongoConn),
+		revisions:  repositories.NewBlogRevisionRepository(dbMongoConn),
+		uploader:   uploader,
+		mediaDir:   filepath.Dir(*seedPath),
+		author:     admin,
+		categories: categories,
+	}
+	if err := loader.load(ctx, seed.DemoPosts); err != nil {
+		appLogger.Errorf("demo content failed: %w", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/spf13/viper"
+)
+
+// seedFile mirrors seed/seed.yaml.
+type seedFile struct {
+	Admin      seedAdmin      `mapstructure:"admin"`
+	Categories []seedCategory `mapstructure:"categories"`
+	DemoPosts  []seedPost     `mapstructure:"demo_posts"`
+}
+
+type seedAdmin struct {
+	Email     string `mapstructure:"email"`
+	Password  string `mapstructure:"password"`
+	Username  string `mapstructure:"username"`
+	FirstName string `mapstructure:"first_name"`
+	LastName  string `mapstructure:"last_name"`
+}
+
+type seedCategory struct {
+	Name        string          `mapstructure:"name"`
+	Description string          `mapstructure:"description"`
+	Access      []constant.Role `mapstructure:"access"`
+	Children    []seedCategory  `mapstructure:"children"`
+}
+
+type seedPost struct {
+	Title      string      `mapstructure:"title"`
+	Categories []string    `mapstructure:"categories"` // category names
+	Blocks     []seedBlock `mapstructure:"blocks"`
+}
+
+// seedBlock sets exactly one of its fields. Image is a file path relative to
+// the seed file.
+type seedBlock struct {
+	Heading string `mapstructure:"heading"`
+	Text    string `mapstructure:"text"`
+	Image   string `mapstructure:"image"`
+}
+
+// loadSeed reads the seed file. The admin credentials never live in the file:
+// they come from SETUP_ADMIN_EMAIL and SETUP_ADMIN_PASSWORD.
+func loadSeed(path string) (*seedFile, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	v.BindEnv("admin.email", "SETUP_ADMIN_EMAIL")
+	v.BindEnv("admin.password", "SETUP_ADMIN_PASSWORD")
+
+	var seed seedFile
+	if err := v.Unmarshal(&seed); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", path, err)
+	}
+	return &seed, nil
+}
@@ -11,10 +11,16 @@ import (
 
 // Config holds the entire application configuration.
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Storage  StorageConfig
-	CORS     CORSConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Storage   StorageConfig
+	CORS      CORSConfig
+	Scheduler SchedulerConfig
+}
+
+// SchedulerConfig holds background worker configurations.
+type SchedulerConfig struct {
+	PublishInterval time.Duration `mapstructure:"publish_interval"`
 }
 
 type StorageConfig struct {
@@ -97,6 +103,8 @@ func LoadConfig() (*Config, error) {
 
 	// Convert CORS.MaxAge from seconds to time.Duration
 	config.CORS.MaxAge = time.Duration(config.CORS.MaxAge) * time.Second
+	// Convert Scheduler.PublishInterval from seconds to time.Duration
+	config.Scheduler.PublishInterval = time.Duration(config.Scheduler.PublishInterval) * time.Second
 
 	// Validate required configurations
 	missing := []string{}
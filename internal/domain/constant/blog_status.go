@@ -3,19 +3,46 @@ package constant
 type BlogStatus string
 
 const (
-	BlogStatusActive   BlogStatus = "active"
-	BlogStatusInactive BlogStatus = "inactive"
-	BlogStatusDeleted  BlogStatus = "deleted"
-	BlogStatusArchived BlogStatus = "archived"
+	BlogStatusDraft     BlogStatus = "draft"
+	BlogStatusInReview  BlogStatus = "in_review"
+	BlogStatusScheduled BlogStatus = "scheduled"
+	BlogStatusPublished BlogStatus = "published"
+	BlogStatusActive    BlogStatus = "active"
+	BlogStatusInactive  BlogStatus = "inactive"
+	BlogStatusDeleted   BlogStatus = "deleted"
+	BlogStatusArchived  BlogStatus = "archived"
 )
 
 var AllBlogStatus = []BlogStatus{
+	BlogStatusDraft,
+	BlogStatusInReview,
+	BlogStatusScheduled,
+	BlogStatusPublished,
 	BlogStatusActive,
 	BlogStatusInactive,
 	BlogStatusDeleted,
 	BlogStatusArchived,
 }
 
+// BlogStatusTransitions lists, for each status, the statuses a post may move to
+// through the editorial workflow. Soft-deletion is handled separately and is not
+// part of the workflow. Active and inactive are legacy values kept for posts
+// created before the workflow existed.
+var BlogStatusTransitions = map[BlogStatus][]BlogStatus{
+	BlogStatusDraft:     {BlogStatusInReview, BlogStatusArchived},
+	BlogStatusInReview:  {BlogStatusDraft, BlogStatusScheduled, BlogStatusPublished},
+	BlogStatusScheduled: {BlogStatusDraft, BlogStatusPublished},
+	BlogStatusPublished: {BlogStatusArchived},
+	BlogStatusArchived:  {BlogStatusDraft},
+	BlogStatusActive:    {BlogStatusDraft, BlogStatusArchived},
+	BlogStatusInactive:  {BlogStatusDraft, BlogStatusArchived},
+}
+
 func IsValidBlogStatus(status BlogStatus) bool {
 	return IsValid(status, AllBlogStatus)
 }
+
+// CanTransitionBlogStatus reports whether a post in status from may move to status to.
+func CanTransitionBlogStatus(from, to BlogStatus) bool {
+	return IsValid(to, BlogStatusTransitions[from])
+}
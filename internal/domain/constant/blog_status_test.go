@@ -0,0 +1,55 @@
+package constant
+
+import "testing"
+
+func TestCanTransitionBlogStatus(t *testing.T) {
+	tests := []struct {
+		from, to BlogStatus
+		want     bool
+	}{
+		{BlogStatusDraft, BlogStatusInReview, true},
+		{BlogStatusDraft, BlogStatusArchived, true},
+		{BlogStatusDraft, BlogStatusPublished, false},
+		{BlogStatusDraft, BlogStatusScheduled, false},
+		{BlogStatusInReview, BlogStatusDraft, true},
+		{BlogStatusInReview, BlogStatusScheduled, true},
+		{BlogStatusInReview, BlogStatusPublished, true},
+		{BlogStatusInReview, BlogStatusArchived, false},
+		{BlogStatusScheduled, BlogStatusPublished, true},
+		{BlogStatusScheduled, BlogStatusDraft, true},
+		{BlogStatusScheduled, BlogStatusArchived, false},
+		{BlogStatusPublished, BlogStatusArchived, true},
+		{BlogStatusPublished, BlogStatusDraft, false},
+		{BlogStatusArchived, BlogStatusDraft, true},
+		{BlogStatusArchived, BlogStatusPublished, false},
+		{BlogStatusActive, BlogStatusDraft, true},
+		{BlogStatusInactive, BlogStatusArchived, true},
+		{BlogStatusActive, BlogStatusPublished, false},
+
+		// Soft-deletion is not part of the workflow, in either direction.
+		{BlogStatusPublished, BlogStatusDeleted, false},
+		{BlogStatusDeleted, BlogStatusDraft, false},
+
+		// Staying put is not a transition.
+		{BlogStatusDraft, BlogStatusDraft, false},
+		{BlogStatus("bogus"), BlogStatusDraft, false},
+	}
+	for _, tc := range tests {
+		if got := CanTransitionBlogStatus(tc.from, tc.to); got != tc.want {
+			t.Errorf("CanTransitionBlogStatus(%s, %s) = %v, want %v", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestBlogStatusTransitionsUseKnownStatuses(t *testing.T) {
+	for from, targets := range BlogStatusTransitions {
+		if !IsValidBlogStatus(from) {
+			t.Errorf("transitions from unknown status %q", from)
+		}
+		for _, to := range targets {
+			if !IsValidBlogStatus(to) {
+				t.Errorf("%s transitions to unknown status %q", from, to)
+			}
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package constant
+
+type CategoryStatus string
+
+const (
+	CategoryStatusActive   CategoryStatus = "active"
+	CategoryStatusArchived CategoryStatus = "archived"
+	CategoryStatusDeleted  CategoryStatus = "deleted"
+)
+
+var AllCategoryStatus = []CategoryStatus{
+	CategoryStatusActive,
+	CategoryStatusArchived,
+	CategoryStatusDeleted,
+}
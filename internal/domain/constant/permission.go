@@ -0,0 +1,49 @@
+package constant
+
+type Permission string
+
+const (
+	PermissionPostRead       Permission = "post:read"
+	PermissionPostWrite      Permission = "post:write"
+	PermissionPostPublish    Permission = "post:publish"
+	PermissionPostBulkWrite  Permission = "post:bulk_write"
+	PermissionCategoryRead   Permission = "category:read"
+	PermissionCategoryManage Permission = "category:manage"
+	PermissionUserManage     Permission = "user:manage"
+)
+
+var AllPermissions = []Permission{
+	PermissionPostRead,
+	PermissionPostWrite,
+	PermissionPostPublish,
+	PermissionPostBulkWrite,
+	PermissionCategoryRead,
+	PermissionCategoryManage,
+	PermissionUserManage,
+}
+
+// RolePermissions is the permission matrix: what each role is allowed to do.
+// Readers (basic, premium) can only browse; cj writers author and publish
+// posts; admins can do everything.
+var RolePermissions = map[Role][]Permission{
+	RoleBasic: {
+		PermissionPostRead,
+		PermissionCategoryRead,
+	},
+	RolePremium: {
+		PermissionPostRead,
+		PermissionCategoryRead,
+	},
+	RoleCJ: {
+		PermissionPostRead,
+		PermissionPostWrite,
+		PermissionPostPublish,
+		PermissionCategoryRead,
+	},
+	RoleAdmin: AllPermissions,
+}
+
+// HasPermission reports whether role grants permission.
+func HasPermission(role Role, permission Permission) bool {
+	return IsValid(permission, RolePermissions[role])
+}
@@ -0,0 +1,31 @@
+package constant
+
+import "testing"
+
+func TestHasPermission(t *testing.T) {
+	// One row per permission: basic, premium, cj, admin.
+	matrix := map[Permission][4]bool{
+		PermissionPostRead:       {true, true, true, true},
+		PermissionPostWrite:      {false, false, true, true},
+		PermissionPostPublish:    {false, false, true, true},
+		PermissionPostBulkWrite:  {false, false, false, true},
+		PermissionCategoryRead:   {true, true, true, true},
+		PermissionCategoryManage: {false, false, false, true},
+		PermissionUserManage:     {false, false, false, true},
+	}
+	roles := [4]Role{RoleBasic, RolePremium, RoleCJ, RoleAdmin}
+
+	if len(matrix) != len(AllPermissions) {
+		t.Fatalf("matrix covers %d permissions, want all %d", len(matrix), len(AllPermissions))
+	}
+	for permission, allowed := range matrix {
+		for i, role := range roles {
+			if got := HasPermission(role, permission); got != allowed[i] {
+				t.Errorf("HasPermission(%s, %s) = %v, want %v", role, permission, got, allowed[i])
+			}
+		}
+		if HasPermission(Role("ghost"), permission) {
+			t.Errorf("unknown role has %s", permission)
+		}
+	}
+}
@@ -25,3 +25,19 @@ var AllAccountStatus = []AccountStatus{
 func IsValidAccountStatus(status AccountStatus) bool {
 	return IsValid(status, AllAccountStatus)
 }
+
+// AccountStatusTransitions lists, for each status, the statuses an admin may
+// move an account to. Approving a pending account and reinstating a suspended
+// or banned one both lead back to active. Deletion is handled separately.
+var AccountStatusTransitions = map[AccountStatus][]AccountStatus{
+	StatusPending:   {StatusActive, StatusBanned},
+	StatusActive:    {StatusSuspended, StatusBanned},
+	StatusSuspended: {StatusActive, StatusBanned},
+	StatusBanned:    {StatusActive},
+	StatusInactive:  {StatusActive, StatusBanned},
+}
+
+// CanTransitionAccountStatus reports whether an account in status from may move to status to.
+func CanTransitionAccountStatus(from, to AccountStatus) bool {
+	return IsValid(to, AccountStatusTransitions[from])
+}
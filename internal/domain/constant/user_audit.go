@@ -0,0 +1,13 @@
+package constant
+
+type UserAuditAction string
+
+const (
+	UserAuditApprove    UserAuditAction = "approve"
+	UserAuditSuspend    UserAuditAction = "suspend"
+	UserAuditBan        UserAuditAction = "ban"
+	UserAuditReinstate  UserAuditAction = "reinstate"
+	UserAuditChangeRole UserAuditAction = "change_role"
+	UserAuditSoftDelete UserAuditAction = "soft_delete"
+	UserAuditHardDelete UserAuditAction = "hard_delete"
+)
@@ -9,12 +9,17 @@ import (
 
 // BlogPost describes the top-level information for a blog post.
 type BlogPost struct {
-	ID         primitive.ObjectID  `json:"id" bson:"_id"`
-	AuthorID   int                 `json:"author_id"`
-	Categories []string            `json:"categories"`
-	Title      string              `json:"title"`
-	Blocks     []Block             `json:"blocks"`
-	Status     constant.BlogStatus `json:"status"`
-	CreatedAt  time.Time           `json:"created_at"`
-	UpdatedAt  time.Time           `json:"updated_at"`
+	ID          primitive.ObjectID   `json:"id" bson:"_id"`
+	AuthorID    uint64               `json:"author_id" bson:"author_id"`
+	EditorIDs   []uint64             `json:"editor_ids" bson:"editor_ids"` // co-authors allowed to edit content
+	Categories  []primitive.ObjectID `json:"categories" bson:"categories"`
+	Title       string               `json:"title" bson:"title"`
+	Slug        string               `json:"slug" bson:"slug"`
+	Blocks      []Block              `json:"blocks" bson:"blocks"`
+	Status      constant.BlogStatus  `json:"status" bson:"status"`
+	Version     int64                `json:"version" bson:"version"` // bumped on every content change
+	PublishAt   *time.Time           `json:"publish_at,omitempty" bson:"publish_at,omitempty"`
+	PublishedAt *time.Time           `json:"published_at,omitempty" bson:"published_at,omitempty"`
+	CreatedAt   time.Time            `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at" bson:"updated_at"`
 }
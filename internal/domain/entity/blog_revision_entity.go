@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BlogRevision is an immutable snapshot of a blog post's content, written every
+// time the post is created, updated or restored.
+type BlogRevision struct {
+	ID           primitive.ObjectID  `json:"id" bson:"_id"`
+	PostID       primitive.ObjectID  `json:"post_id" bson:"post_id"`
+	AuthorID     uint64              `json:"author_id" bson:"author_id"`
+	Title        string              `json:"title" bson:"title"`
+	Blocks       []Block             `json:"blocks" bson:"blocks"`
+	RestoredFrom *primitive.ObjectID `json:"restored_from,omitempty" bson:"restored_from,omitempty"`
+	CreatedAt    time.Time           `json:"created_at" bson:"created_at"`
+}
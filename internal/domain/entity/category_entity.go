@@ -1,17 +1,39 @@
 package entity
 
 import (
+	"strings"
 	"time"
 
 	"github.com/capigiba/capiary/internal/domain/constant"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Category is a node in the category tree. Ancestors is the materialized path
+// from the root down to the direct parent, so descendants can be found with a
+// single {"ancestors": id} query.
 type Category struct {
-	ID          primitive.ObjectID `json:"id" bson:"_id"`
-	Name        string             `json:"name"`
-	Description string             `json:"description"`
-	Access      []constant.Role    `json:"access"`
-	CreatedAt   time.Time          `json:"created_at"`
-	UpdatedAt   time.Time          `json:"updated_at"`
+	ID             primitive.ObjectID      `json:"id" bson:"_id"`
+	Name           string                  `json:"name" bson:"name"`
+	NormalizedName string                  `json:"-" bson:"normalized_name"` // case- and space-insensitive key behind the unique index
+	Description    string                  `json:"description" bson:"description"`
+	Access         []constant.Role         `json:"access" bson:"access"` // roles allowed to read its posts; empty means public
+	Status         constant.CategoryStatus `json:"status" bson:"status"`
+	MergedInto     *primitive.ObjectID     `json:"merged_into,omitempty" bson:"merged_into,omitempty"` // set when deleted by a merge
+	ParentID       *primitive.ObjectID     `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Ancestors      []primitive.ObjectID    `json:"ancestors" bson:"ancestors"`
+	Breadcrumbs    []Breadcrumb            `json:"breadcrumbs,omitempty" bson:"-"` // computed on read, root first, ending with the category itself
+	CreatedAt      time.Time               `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at" bson:"updated_at"`
+}
+
+// Breadcrumb is one step of a category's path, e.g. Engineering > Backend > Go.
+type Breadcrumb struct {
+	ID   primitive.ObjectID `json:"id"`
+	Name string             `json:"name"`
+}
+
+// NormalizeCategoryName folds case and collapses whitespace, so "Go", "go"
+// and " Go " all share one key.
+func NormalizeCategoryName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
 }
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+)
+
+// UserAuditEntry records one admin action on a user account. From and To
+// hold the status or role before and after the change, where there is one.
+type UserAuditEntry struct {
+	ID        uint64                   `json:"id" db:"id"`
+	ActorID   uint64                   `json:"actor_id" db:"actor_id"`
+	TargetID  uint64                   `json:"target_id" db:"target_id"`
+	Action    constant.UserAuditAction `json:"action" db:"action"`
+	From      string                   `json:"from,omitempty" db:"from_value"`
+	To        string                   `json:"to,omitempty" db:"to_value"`
+	Reason    string                   `json:"reason,omitempty" db:"reason"`
+	CreatedAt time.Time                `json:"created_at" db:"created_at"`
+}
@@ -14,6 +14,7 @@ type User struct {
 	Email         string                 `json:"email" db:"email"`
 	Password      string                 `json:"password" db:"password"`
 	Status        constant.AccountStatus `json:"status" db:"status"`
+	StatusReason  string                 `json:"status_reason,omitempty" db:"status_reason"`
 	Role          constant.Role          `json:"role" db:"role"`
 	Avatar        string                 `json:"avatar" db:"avatar"`
 	AvatarFolder  string                 `json:"avatar_folder" db:"avatar_folder"`
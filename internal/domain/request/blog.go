@@ -1,11 +1,17 @@
 package request
 
-import "github.com/capigiba/capiary/internal/domain/constant"
+import (
+	"errors"
+	"time"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type CreateBlogPostRequest struct {
 	Title      string               `json:"title"`
 	Blocks     []CreateBlockRequest `json:"blocks"`
-	AuthorID   int                  `json:"author_id"`
 	Categories []string             `json:"categories"`
 }
 
@@ -27,6 +33,69 @@ type CreateBlockRequest struct {
 	FileData string `json:"file_data,omitempty"` // If using base64, for instance
 }
 
+// ObjectIDsFromHex parses a list of hex IDs, e.g. the categories of a post.
+func ObjectIDsFromHex(hexes []string) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(hexes))
+	for _, hex := range hexes {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, errors.New("invalid id: " + hex)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// ToEntity converts the request into an entity.Block, using fallbackID when no ID was sent.
+func (b CreateBlockRequest) ToEntity(fallbackID int) (entity.Block, error) {
+	id := b.ID
+	if id == 0 {
+		id = fallbackID
+	}
+	blk := entity.Block{ID: id, Order: b.Order}
+	switch b.Type {
+	case constant.MediaTypeText:
+		blk.Type = entity.BlockTypeText
+		var paras []entity.Paragraph
+		for j, p := range b.Paragraphs {
+			para := entity.Paragraph{
+				ID:    j + 1,
+				Text:  p.Text,
+				Align: p.Align,
+			}
+			for _, f := range p.Formats {
+				para.Formats = append(para.Formats, entity.Format{
+					Type:      entity.FormatType(f.Type),
+					Start:     f.Start,
+					End:       f.End,
+					Hyperlink: f.Hyperlink,
+				})
+			}
+			paras = append(paras, para)
+		}
+		blk.Text = &entity.TextBlock{Paragraphs: paras}
+
+	case constant.MediaTypeHeading:
+		if b.HeadingLevel == nil || b.Text == nil {
+			return entity.Block{}, errors.New("heading requires level & text")
+		}
+		blk.Type = entity.BlockTypeHeading
+		blk.Heading = &entity.HeadingBlock{Level: *b.HeadingLevel, Text: *b.Text}
+
+	case constant.MediaTypeImage:
+		blk.Type = entity.BlockTypeImage
+		blk.Image = &entity.ImageBlock{Filename: b.Filename}
+
+	case constant.MediaTypeVideo:
+		blk.Type = entity.BlockTypeVideo
+		blk.Video = &entity.VideoBlock{Filename: b.Filename}
+
+	default:
+		return entity.Block{}, errors.New("unsupported block type: " + string(b.Type))
+	}
+	return blk, nil
+}
+
 // CreateParagraphRequest mirrors the entity.Paragraph
 type CreateParagraphRequest struct {
 	ID      int                   `json:"id"`
@@ -42,3 +111,48 @@ type CreateFormatRequest struct {
 	End       int     `json:"end"`
 	Hyperlink *string `json:"hyperlink,omitempty"`
 }
+
+// TransitionBlogPostRequest asks for a post to be moved to another workflow status.
+// PublishAt is required when scheduling a post.
+type TransitionBlogPostRequest struct {
+	Status    constant.BlogStatus `json:"status" binding:"required"`
+	PublishAt *time.Time          `json:"publish_at,omitempty"`
+}
+
+// PatchBlogPostRequest carries a partial update; nil fields are left untouched.
+// Operations are applied in order, all-or-nothing.
+type PatchBlogPostRequest struct {
+	Title      *string                 `json:"title,omitempty"`
+	Categories *[]string               `json:"categories,omitempty"`
+	Operations []BlockOperationRequest `json:"operations,omitempty"`
+}
+
+// BlockOperationType names a single-block edit.
+type BlockOperationType string
+
+const (
+	BlockOpInsert  BlockOperationType = "insert"
+	BlockOpMove    BlockOperationType = "move"
+	BlockOpReplace BlockOperationType = "replace"
+	BlockOpDelete  BlockOperationType = "delete"
+)
+
+// BlockOperationRequest edits one block, addressed by its ID.
+//   - insert:  Block at Position (appended when Position is nil)
+//   - move:    BlockID to Position
+//   - replace: BlockID with Block (the block keeps its ID and position)
+//   - delete:  BlockID
+//
+// Position is a zero-based index into the post's blocks. For image and video
+// blocks the new file is sent as the multipart field "operation_<i>_file".
+type BlockOperationRequest struct {
+	Op       BlockOperationType  `json:"op"`
+	BlockID  int                 `json:"block_id,omitempty"`
+	Position *int                `json:"position,omitempty"`
+	Block    *CreateBlockRequest `json:"block,omitempty"`
+}
+
+// UpdatePostEditorsRequest replaces the list of co-authors allowed to edit a post.
+type UpdatePostEditorsRequest struct {
+	EditorIDs []uint64 `json:"editor_ids"`
+}
@@ -1,11 +1,25 @@
 package request
 
+import "github.com/capigiba/capiary/internal/domain/constant"
+
 type CreateCategoryRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
+	Name        string          `json:"name" binding:"required"`
+	Description string          `json:"description"`
+	Access      []constant.Role `json:"access"`
+	ParentID    string          `json:"parent_id"`
+}
+
+// MergeCategoryRequest names the category that absorbs the one in the path.
+type MergeCategoryRequest struct {
+	TargetID string `json:"target_id" binding:"required"`
 }
 
+// UpdateCategoryRequest leaves Access untouched when it is omitted;
+// an empty list makes the category public again. Likewise an omitted
+// ParentID keeps the category where it is and "" moves it to the root.
 type UpdateCategoryRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
+	Name        string           `json:"name" binding:"required"`
+	Description string           `json:"description"`
+	Access      *[]constant.Role `json:"access"`
+	ParentID    *string          `json:"parent_id"`
 }
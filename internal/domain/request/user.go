@@ -0,0 +1,13 @@
+package request
+
+import "github.com/capigiba/capiary/internal/domain/constant"
+
+// ModerateUserRequest carries the reason for a suspension or ban. Approving
+// and reinstating need no body.
+type ModerateUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ChangeUserRoleRequest struct {
+	Role constant.Role `json:"role" binding:"required"`
+}
@@ -0,0 +1,46 @@
+package response
+
+import "github.com/capigiba/capiary/internal/domain/entity"
+
+// BlockChangeType describes how a block differs between two revisions.
+type BlockChangeType string
+
+const (
+	BlockChangeAdded    BlockChangeType = "added"
+	BlockChangeRemoved  BlockChangeType = "removed"
+	BlockChangeModified BlockChangeType = "modified"
+	BlockChangeMoved    BlockChangeType = "moved"
+)
+
+// BlockChange is a single block-level difference, matched by Block.ID.
+// From is nil for added blocks and To is nil for removed blocks.
+type BlockChange struct {
+	BlockID int             `json:"block_id"`
+	Type    BlockChangeType `json:"type"`
+	From    *entity.Block   `json:"from,omitempty"`
+	To      *entity.Block   `json:"to,omitempty"`
+}
+
+// RevisionDiff describes what changed between two revisions of the same post.
+type RevisionDiff struct {
+	FromRevision string        `json:"from_revision"`
+	ToRevision   string        `json:"to_revision"`
+	TitleChanged bool          `json:"title_changed"`
+	FromTitle    string        `json:"from_title,omitempty"`
+	ToTitle      string        `json:"to_title,omitempty"`
+	Blocks       []BlockChange `json:"blocks"`
+}
+
+// PostSearchHit is one search result: the post, its relevance score and
+// excerpts with the matched words wrapped in <mark>.
+type PostSearchHit struct {
+	Post     entity.BlogPost `json:"post"`
+	Score    float64         `json:"score"`
+	Snippets []string        `json:"snippets"`
+}
+
+// PostSearchResult is one page of search hits and the total number of matches.
+type PostSearchResult struct {
+	Hits  []PostSearchHit
+	Total int64
+}
@@ -0,0 +1,9 @@
+package response
+
+import "github.com/capigiba/capiary/internal/domain/entity"
+
+// CategoryNode is a category together with its nested sub-categories.
+type CategoryNode struct {
+	entity.Category
+	Children []*CategoryNode `json:"children"`
+}
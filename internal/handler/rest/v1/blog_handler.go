@@ -2,14 +2,18 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/capigiba/capiary/internal/domain/constant"
 	"github.com/capigiba/capiary/internal/domain/entity"
 	"github.com/capigiba/capiary/internal/domain/request"
+	"github.com/capigiba/capiary/internal/infra/db/query"
+	"github.com/capigiba/capiary/internal/middleware"
 	"github.com/capigiba/capiary/internal/services"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -76,11 +80,22 @@ func (h *BlogPostHandler) CreateBlogPostHandler(c *gin.Context) {
 
 	// 5) Now that all data is in place, do the normal create:
 	//    This calls h.service.CreatePostWithFiles(c, post) in your code
+	// The author always comes from the token, never from the request body.
+	user := middleware.CurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	categories, err := request.ObjectIDsFromHex(req.Categories)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid categories: " + err.Error()})
+		return
+	}
 	post := entity.BlogPost{
-		ID:    primitive.NewObjectID(),
-		Title: req.Title,
-		// AuthorID:   req.AuthorID,
-		// Categories: req.Categories,
+		ID:         primitive.NewObjectID(),
+		Title:      req.Title,
+		AuthorID:   user.ID,
+		Categories: categories,
 	}
 
 	// Rebuild the blocks array from req.Blocks, just like your snippet does:
@@ -146,7 +161,7 @@ func (h *BlogPostHandler) CreateBlogPostHandler(c *gin.Context) {
 	// Actually call your service
 	insertedID, err := h.service.CreatePostWithFiles(c, post)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondBlogError(c, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -156,8 +171,11 @@ func (h *BlogPostHandler) CreateBlogPostHandler(c *gin.Context) {
 // Find blog posts with raw filter/sort/fields
 func (h *BlogPostHandler) FindBlogPostsHandler(c *gin.Context) {
 	rawFilters := c.QueryArray("filter") // e.g. ["age__gt__30", "title__==__Hello"]
+	rawWhere := c.Query("where")         // e.g. "or(status__==__draft;author_id__==__5)"
 	rawSorts := c.QueryArray("sort")     // e.g. ["age__desc", "title__asc"]
 	rawFields := c.Query("fields")       // e.g. "id,title"
+	cursor := c.Query("cursor")          // next_cursor or prev_cursor from a previous page; overrides page
+	includeDescendants, _ := strconv.ParseBool(c.DefaultQuery("include_descendants", "false"))
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
@@ -168,22 +186,19 @@ func (h *BlogPostHandler) FindBlogPostsHandler(c *gin.Context) {
 		pageSize = 10
 	}
 
-	posts, err := h.service.FindPostsWithRawQuery(c.Request.Context(), rawFilters, rawSorts, rawFields, page, pageSize)
+	posts, pageInfo, err := h.service.FindPostsWithRawQuery(c, rawFilters, rawWhere, rawSorts, rawFields, includeDescendants, page, pageSize, cursor, wantsTotal(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"data": posts,
-		"meta": gin.H{
-			"page":      page,
-			"page_size": pageSize,
-			"count":     len(posts),
-		},
+		"meta": listMeta(c, page, pageSize, len(posts), pageInfo),
 	})
 }
 
-// Update a blog post using a filter
+// Update a blog post using a filter, which must match exactly one post.
+// An If-Match header carrying the post's ETag makes the update fail with 409 if the post changed since.
 func (h *BlogPostHandler) UpdateBlogPostHandler(c *gin.Context) {
 	rawFilters := c.QueryArray("filter") // e.g. ["_id__==__<ObjectID>"]
 
@@ -192,16 +207,38 @@ func (h *BlogPostHandler) UpdateBlogPostHandler(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := bindUpdateForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	version, err := h.service.UpdatePostByRawFilter(c, rawFilters, post, expectedVersion)
+	if err != nil {
+		respondBlogError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.Header("ETag", formatETag(version))
+	c.JSON(http.StatusOK, gin.H{"status": "post updated", "version": version})
+}
+
+// bindUpdateForm reads the multipart "metadata" field and per-block files of an
+// update request, stashing file bytes on the context for the service.
+func bindUpdateForm(c *gin.Context) (entity.BlogPost, error) {
 	metaJSON := c.PostForm("metadata")
 	if metaJSON == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing metadata field"})
-		return
+		return entity.BlogPost{}, errors.New("missing metadata field")
 	}
 
 	var req request.CreateBlogPostRequest // reuse same shape
 	if err := json.Unmarshal([]byte(metaJSON), &req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid metadata: " + err.Error()})
-		return
+		return entity.BlogPost{}, errors.New("invalid metadata: " + err.Error())
 	}
 
 	for i, b := range req.Blocks {
@@ -221,65 +258,28 @@ func (h *BlogPostHandler) UpdateBlogPostHandler(c *gin.Context) {
 	post := entity.BlogPost{
 		Title: req.Title,
 	}
+	if req.Categories != nil {
+		categories, err := request.ObjectIDsFromHex(req.Categories)
+		if err != nil {
+			return entity.BlogPost{}, errors.New("invalid categories: " + err.Error())
+		}
+		post.Categories = categories
+	}
 	var blocks []entity.Block
 	for i, b := range req.Blocks {
-		id := b.ID
-		if id == 0 {
-			id = i + 1
-		}
-		blk := entity.Block{ID: id, Order: b.Order}
-		switch b.Type {
-		case constant.MediaTypeText:
-			blk.Type = entity.BlockTypeText
-			var paras []entity.Paragraph
-			for j, p := range b.Paragraphs {
-				para := entity.Paragraph{
-					ID:    j + 1,
-					Text:  p.Text,
-					Align: p.Align,
-				}
-				for _, f := range p.Formats {
-					para.Formats = append(para.Formats, entity.Format{
-						Type:      entity.FormatType(f.Type),
-						Start:     f.Start,
-						End:       f.End,
-						Hyperlink: f.Hyperlink,
-					})
-				}
-				paras = append(paras, para)
-			}
-			blk.Text = &entity.TextBlock{Paragraphs: paras}
-
-		case constant.MediaTypeHeading:
-			if b.HeadingLevel == nil || b.Text == nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "heading requires level & text"})
-				return
-			}
-			blk.Type = entity.BlockTypeHeading
-			blk.Heading = &entity.HeadingBlock{Level: *b.HeadingLevel, Text: *b.Text}
-
-		case constant.MediaTypeImage:
-			blk.Type = entity.BlockTypeImage
-			blk.Image = &entity.ImageBlock{Filename: b.Filename}
-
-		case constant.MediaTypeVideo:
-			blk.Type = entity.BlockTypeVideo
-			blk.Video = &entity.VideoBlock{Filename: b.Filename}
+		blk, err := b.ToEntity(i + 1)
+		if err != nil {
+			return entity.BlogPost{}, err
 		}
 		blocks = append(blocks, blk)
 	}
 	post.Blocks = blocks
 
-	err := h.service.UpdatePostByRawFilter(c, rawFilters, post)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"status": "post updated"})
+	return post, nil
 }
 
 func (h *BlogPostHandler) LoadAllPostsHandler(c *gin.Context) {
-	posts, err := h.service.LoadAllPosts(c.Request.Context())
+	posts, err := h.service.LoadAllPosts(c)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -296,9 +296,309 @@ func (h *BlogPostHandler) DeleteBlogPostHandler(c *gin.Context) {
 	}
 
 	if err := h.service.SoftDeletePostByRawFilter(c.Request.Context(), rawFilters); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondBlogError(c, err, http.StatusInternalServerError)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "post deleted (soft)"})
 }
+
+// FindPostsByCategoryHandler lists the posts of one category, and of its
+// sub-categories when include_descendants=true. Paging works as in FindBlogPostsHandler.
+// e.g. GET /categories/:id/posts?page=1&page_size=10&include_descendants=true
+func (h *BlogPostHandler) FindPostsByCategoryHandler(c *gin.Context) {
+	cursor := c.Query("cursor")
+	includeDescendants, _ := strconv.ParseBool(c.DefaultQuery("include_descendants", "false"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	posts, pageInfo, err := h.service.FindPostsByCategory(c, c.Param("id"), includeDescendants, page, pageSize, cursor, wantsTotal(c))
+	if errors.Is(err, services.ErrCategoryNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		respondBlogError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": posts,
+		"meta": listMeta(c, page, pageSize, len(posts), pageInfo),
+	})
+}
+
+// SearchBlogPostsHandler runs a full-text search over titles, headings and
+// paragraphs, best match first.
+// e.g. GET /blog/posts/search?q=golang+generics&page=1&page_size=10
+func (h *BlogPostHandler) SearchBlogPostsHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	result, err := h.service.SearchPosts(c, c.Query("q"), page, pageSize)
+	if err != nil {
+		respondBlogError(c, err, http.StatusInternalServerError)
+		return
+	}
+	// Hits are ranked by score, so search pages by number only; the total is always known.
+	pageInfo := query.PageInfo{
+		HasNext: int64(page*pageSize) < result.Total,
+		HasPrev: page > 1,
+		Total:   &result.Total,
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": result.Hits,
+		"meta": listMeta(c, page, pageSize, len(result.Hits), pageInfo),
+	})
+}
+
+// GetBlogPostHandler returns a single post by its ID.
+// e.g. GET /blog/posts/:id
+func (h *BlogPostHandler) GetBlogPostHandler(c *gin.Context) {
+	post, err := h.service.FindPostByID(c, c.Param("id"))
+	if err != nil {
+		respondBlogError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.Header("ETag", formatETag(post.Version))
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
+// GetBlogPostBySlugHandler returns a single post by its slug.
+// e.g. GET /blog/posts/by-slug/:slug
+func (h *BlogPostHandler) GetBlogPostBySlugHandler(c *gin.Context) {
+	post, err := h.service.FindPostBySlug(c, c.Param("slug"))
+	if err != nil {
+		respondBlogError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.Header("ETag", formatETag(post.Version))
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
+// UpdateBlogPostByIDHandler replaces the title and blocks of a single post.
+// It takes the same multipart form as UpdateBlogPostHandler and honours If-Match.
+// e.g. PUT /blog/posts/:id
+func (h *BlogPostHandler) UpdateBlogPostByIDHandler(c *gin.Context) {
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	post, err := bindUpdateForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	version, err := h.service.UpdatePostByID(c, c.Param("id"), post, expectedVersion)
+	if err != nil {
+		respondBlogError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.Header("ETag", formatETag(version))
+	c.JSON(http.StatusOK, gin.H{"status": "post updated", "version": version})
+}
+
+// PatchBlogPostHandler updates only the fields present in the body and applies
+// block-level operations. The body is JSON, or a multipart form with the JSON in
+// "metadata" when operations carry new media files.
+// e.g. PATCH /blog/posts/:id {"operations": [{"op": "delete", "block_id": 3}]}
+func (h *BlogPostHandler) PatchBlogPostHandler(c *gin.Context) {
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req request.PatchBlogPostRequest
+	if c.ContentType() == "multipart/form-data" {
+		if err := json.Unmarshal([]byte(c.PostForm("metadata")), &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid metadata: " + err.Error()})
+			return
+		}
+		for i := range req.Operations {
+			fh, err := c.FormFile(fmt.Sprintf("operation_%d_file", i))
+			if err != nil {
+				continue
+			}
+			f, err := fh.Open()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			defer f.Close()
+			fileBytes, err := io.ReadAll(f)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.Set(fmt.Sprintf("operation_%d_fileBytes", i), fileBytes)
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON: " + err.Error()})
+		return
+	}
+
+	post, err := h.service.PatchPost(c, c.Param("id"), req, expectedVersion)
+	if err != nil {
+		respondBlogError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.Header("ETag", formatETag(post.Version))
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
+// DeleteBlogPostByIDHandler soft-deletes a single post.
+// e.g. DELETE /blog/posts/:id
+func (h *BlogPostHandler) DeleteBlogPostByIDHandler(c *gin.Context) {
+	if err := h.service.SoftDeletePostByID(c, c.Param("id")); err != nil {
+		respondBlogError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "post deleted (soft)"})
+}
+
+// UpdatePostEditorsHandler replaces the co-authors allowed to edit a post.
+// e.g. PUT /blog/posts/:id/editors {"editor_ids": [4, 7]}
+func (h *BlogPostHandler) UpdatePostEditorsHandler(c *gin.Context) {
+	var req request.UpdatePostEditorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON: " + err.Error()})
+		return
+	}
+
+	post, err := h.service.SetPostEditors(c, c.Param("id"), req.EditorIDs)
+	if err != nil {
+		respondBlogError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.Header("ETag", formatETag(post.Version))
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
+// TransitionBlogPostHandler moves a post to another workflow status.
+// e.g. POST /blog/posts/:id/transition {"status": "in_review"}
+func (h *BlogPostHandler) TransitionBlogPostHandler(c *gin.Context) {
+	var req request.TransitionBlogPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON: " + err.Error()})
+		return
+	}
+
+	post, err := h.service.TransitionPost(c, c.Param("id"), req.Status, req.PublishAt)
+	if err != nil {
+		respondBlogError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.Header("ETag", formatETag(post.Version))
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
+// ListRevisionsHandler lists every revision of a post, newest first.
+// e.g. GET /blog/posts/:id/revisions
+func (h *BlogPostHandler) ListRevisionsHandler(c *gin.Context) {
+	revisions, err := h.service.ListRevisions(c, c.Param("id"))
+	if err != nil {
+		respondBlogError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": revisions,
+		"meta": gin.H{
+			"count": len(revisions),
+		},
+	})
+}
+
+// DiffRevisionsHandler shows the block-level diff between two revisions of a post.
+// e.g. GET /blog/posts/:id/revisions/diff?from=<revisionId>&to=<revisionId>
+func (h *BlogPostHandler) DiffRevisionsHandler(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing from or to revision"})
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(c, c.Param("id"), from, to)
+	if err != nil {
+		respondBlogError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": diff})
+}
+
+// RestoreRevisionHandler makes an older revision the current content of a post.
+// e.g. POST /blog/posts/:id/revisions/:revision_id/restore
+func (h *BlogPostHandler) RestoreRevisionHandler(c *gin.Context) {
+	post, err := h.service.RestoreRevision(c, c.Param("id"), c.Param("revision_id"))
+	if err != nil {
+		respondBlogError(c, err, http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("ETag", formatETag(post.Version))
+	c.JSON(http.StatusOK, gin.H{"data": post})
+}
+
+// respondBlogError maps the service's sentinel errors to HTTP statuses,
+// falling back to the given status for anything else.
+func respondBlogError(c *gin.Context, err error, fallback int) {
+	status := fallback
+	var fieldErr *query.FieldError
+	switch {
+	case errors.As(err, &fieldErr):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrPostNotFound), errors.Is(err, services.ErrRevisionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrCategoryNotFound), errors.Is(err, services.ErrCategoryArchived):
+		// Referencing a missing or archived category is a validation error on the post.
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrReadForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrEmptySearchQuery), errors.Is(err, services.ErrAmbiguousFilter):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrInvalidStatusTransition), errors.Is(err, services.ErrVersionConflict):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// formatETag renders a post version as a strong ETag, e.g. "3".
+func formatETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// parseIfMatch reads the post version out of an If-Match header.
+// It returns nil when the header is empty or "*", meaning no version check.
+func parseIfMatch(header string) (*int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid If-Match header: %s", header)
+	}
+	return &version, nil
+}
@@ -0,0 +1,55 @@
+package handler
+
+import "testing"
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    int64 // -1 means no version check
+		wantErr bool
+	}{
+		{``, -1, false},
+		{`*`, -1, false},
+		{` * `, -1, false},
+		{`"3"`, 3, false},
+		{`W/"3"`, 3, false},
+		{`3`, 3, false},
+		{` "0" `, 0, false},
+		{`"abc"`, 0, true},
+		{`"3", "4"`, 0, true},
+		{`""`, 0, true},
+	}
+	for _, tc := range tests {
+		got, err := parseIfMatch(tc.header)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("parseIfMatch(%q) = %v, want an error", tc.header, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseIfMatch(%q) failed: %v", tc.header, err)
+			continue
+		}
+		if tc.want == -1 {
+			if got != nil {
+				t.Errorf("parseIfMatch(%q) = %d, want no version check", tc.header, *got)
+			}
+		} else if got == nil || *got != tc.want {
+			t.Errorf("parseIfMatch(%q) = %v, want %d", tc.header, got, tc.want)
+		}
+	}
+}
+
+func TestFormatETagRoundTrips(t *testing.T) {
+	for _, version := range []int64{0, 1, 42} {
+		tag := formatETag(version)
+		if tag[0] != '"' || tag[len(tag)-1] != '"' {
+			t.Errorf("formatETag(%d) = %s, want a quoted strong ETag", version, tag)
+		}
+		got, err := parseIfMatch(tag)
+		if err != nil || got == nil || *got != version {
+			t.Errorf("parseIfMatch(formatETag(%d)) = %v, %v", version, got, err)
+		}
+	}
+}
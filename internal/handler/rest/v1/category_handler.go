@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/capigiba/capiary/internal/domain/constant"
 	"github.com/capigiba/capiary/internal/domain/entity"
 	"github.com/capigiba/capiary/internal/domain/request"
+	"github.com/capigiba/capiary/internal/infra/db/query"
 	"github.com/capigiba/capiary/internal/services"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -33,23 +37,41 @@ func (h *CategoryHandler) CreateCategoryHandler(c *gin.Context) {
 		ID:          primitive.NewObjectID(),
 		Name:        req.Name,
 		Description: req.Description,
+		Access:      req.Access,
+	}
+	if req.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parent_id: " + err.Error()})
+			return
+		}
+		category.ParentID = &parentID
 	}
 
 	insertedID, err := h.service.Create(c, category)
+	if errors.Is(err, services.ErrCategoryNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Failed to create category: " + err.Error()})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category: " + err.Error()})
+		respondCategoryError(c, fmt.Errorf("Failed to create category: %w", err), http.StatusInternalServerError)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"inserted_id": insertedID})
 }
 
-// FindCategoriesHandler finds categories based on raw filter/sort/fields in query params.
+// FindCategoriesHandler finds categories based on raw filter/where/sort/fields in query params.
 // e.g. GET /categories?filter=name__==__someName&sort=name__asc&fields=name,description
+// or   GET /categories?where=or(name__contains__go;parent_id__exists__false)
+// A cursor from a previous page's meta (next_cursor/prev_cursor) takes precedence over page;
+// count=false skips computing total and total_pages.
 func (h *CategoryHandler) FindCategoriesHandler(c *gin.Context) {
 	rawFilters := c.QueryArray("filter")
+	rawWhere := c.Query("where")
 	rawSorts := c.QueryArray("sort")
 	rawFields := c.Query("fields")
+	cursor := c.Query("cursor")
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
@@ -60,7 +82,7 @@ func (h *CategoryHandler) FindCategoriesHandler(c *gin.Context) {
 		pageSize = 10
 	}
 
-	categories, err := h.service.Find(c.Request.Context(), rawFilters, rawSorts, rawFields, page, pageSize)
+	categories, pageInfo, err := h.service.Find(c.Request.Context(), rawFilters, rawWhere, rawSorts, rawFields, page, pageSize, cursor, wantsTotal(c))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -68,11 +90,7 @@ func (h *CategoryHandler) FindCategoriesHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": categories,
-		"meta": gin.H{
-			"page":      page,
-			"page_size": pageSize,
-			"count":     len(categories),
-		},
+		"meta": listMeta(c, page, pageSize, len(categories), pageInfo),
 	})
 }
 
@@ -95,15 +113,72 @@ func (h *CategoryHandler) UpdateCategoryHandler(c *gin.Context) {
 		Name:        req.Name,
 		Description: req.Description,
 	}
+	if req.Access != nil {
+		// Keep a non-nil slice so an explicit [] clears the restriction.
+		update.Access = append([]constant.Role{}, (*req.Access)...)
+	}
 
-	if err := h.service.UpdateByRawFilter(c, rawFilters, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.UpdateByRawFilter(c, rawFilters, update, req.ParentID); err != nil {
+		respondCategoryError(c, err, http.StatusInternalServerError)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "category updated"})
 }
 
+// DeleteCategoryHandler deletes a category. It fails with 409 while posts still
+// reference it, unless cascade=true asks to detach it from those posts first.
+// e.g. DELETE /categories/:id?cascade=true
+func (h *CategoryHandler) DeleteCategoryHandler(c *gin.Context) {
+	cascade, _ := strconv.ParseBool(c.DefaultQuery("cascade", "false"))
+
+	detached, err := h.service.Delete(c.Request.Context(), c.Param("id"), cascade)
+	if err != nil {
+		respondCategoryError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "category deleted", "posts_updated": detached})
+}
+
+// ArchiveCategoryHandler archives a category so no new posts can be filed under it.
+// e.g. POST /categories/:id/archive
+func (h *CategoryHandler) ArchiveCategoryHandler(c *gin.Context) {
+	if err := h.service.Archive(c.Request.Context(), c.Param("id")); err != nil {
+		respondCategoryError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "category archived"})
+}
+
+// UnarchiveCategoryHandler makes an archived category active again.
+// e.g. POST /categories/:id/unarchive
+func (h *CategoryHandler) UnarchiveCategoryHandler(c *gin.Context) {
+	if err := h.service.Unarchive(c.Request.Context(), c.Param("id")); err != nil {
+		respondCategoryError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "category unarchived"})
+}
+
+// MergeCategoryHandler folds the category in the path into target_id and
+// reports how many posts were re-pointed.
+// e.g. POST /categories/:id/merge {"target_id": "<someObjectId>"}
+func (h *CategoryHandler) MergeCategoryHandler(c *gin.Context) {
+	var req request.MergeCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON: " + err.Error()})
+		return
+	}
+
+	updated, err := h.service.Merge(c.Request.Context(), c.Param("id"), req.TargetID)
+	if err != nil {
+		respondCategoryError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "category merged", "posts_updated": updated})
+}
+
 // LoadAllCategoriesHandler loads all categories in ascending order by name.
 func (h *CategoryHandler) LoadAllCategoriesHandler(c *gin.Context) {
 	categories, err := h.service.LoadAll(c.Request.Context())
@@ -113,3 +188,36 @@ func (h *CategoryHandler) LoadAllCategoriesHandler(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, categories)
 }
+
+// CategoryTreeHandler returns all categories nested under their parents.
+// e.g. GET /categories/tree
+func (h *CategoryHandler) CategoryTreeHandler(c *gin.Context) {
+	tree, err := h.service.Tree(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load category tree: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tree})
+}
+
+// respondCategoryError maps the service's sentinel errors to HTTP statuses,
+// falling back to the given status for anything else.
+func respondCategoryError(c *gin.Context, err error, fallback int) {
+	status := fallback
+	var conflict *services.ConflictError
+	var fieldErr *query.FieldError
+	switch {
+	case errors.As(err, &fieldErr):
+		status = http.StatusBadRequest
+	case errors.As(err, &conflict):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrCategoryNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrCategoryInUse), errors.Is(err, services.ErrCategoryHasChildren):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrCategoryCycle), errors.Is(err, services.ErrCategoryArchived),
+		errors.Is(err, services.ErrInvalidAccessRole):
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
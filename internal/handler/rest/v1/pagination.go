@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/capigiba/capiary/internal/infra/db/query"
+	"github.com/gin-gonic/gin"
+)
+
+// wantsTotal reads the ?count= opt-out. Counting runs a second query over the
+// whole listing, so clients that only page forward can skip it with count=false.
+func wantsTotal(c *gin.Context) bool {
+	withTotal, err := strconv.ParseBool(c.DefaultQuery("count", "true"))
+	return err != nil || withTotal
+}
+
+// listMeta builds the meta block of a list response and sets the matching
+// RFC 8288 Link header. total and total_pages are left out when not counted.
+func listMeta(c *gin.Context, page, pageSize, count int, info query.PageInfo) gin.H {
+	meta := gin.H{
+		"page":        page,
+		"page_size":   pageSize,
+		"count":       count,
+		"has_next":    info.HasNext,
+		"next_cursor": info.NextCursor,
+		"prev_cursor": info.PrevCursor,
+	}
+	if info.Total != nil {
+		meta["total"] = *info.Total
+		meta["total_pages"] = info.TotalPages(pageSize)
+	}
+	setLinkHeader(c, page, pageSize, info)
+	return meta
+}
+
+// setLinkHeader advertises the neighbouring pages. A request that paged by
+// cursor gets cursor links; a page-number request gets page-number links.
+func setLinkHeader(c *gin.Context, page, pageSize int, info query.PageInfo) {
+	var links []string
+	add := func(rel string, set map[string]string) {
+		values := c.Request.URL.Query()
+		values.Del("cursor")
+		values.Del("page")
+		for k, v := range set {
+			values.Set(k, v)
+		}
+		links = append(links, fmt.Sprintf(`<%s?%s>; rel="%s"`, c.Request.URL.Path, values.Encode(), rel))
+	}
+
+	add("first", map[string]string{"page": "1"})
+	if c.Query("cursor") != "" {
+		if info.HasPrev {
+			add("prev", map[string]string{"cursor": info.PrevCursor})
+		}
+		if info.HasNext {
+			add("next", map[string]string{"cursor": info.NextCursor})
+		}
+	} else {
+		if info.HasPrev {
+			add("prev", map[string]string{"page": strconv.Itoa(page - 1)})
+		}
+		if info.HasNext {
+			add("next", map[string]string{"page": strconv.Itoa(page + 1)})
+		}
+	}
+	if info.Total != nil {
+		if last := info.TotalPages(pageSize); last > 0 {
+			add("last", map[string]string{"page": strconv.FormatInt(last, 10)})
+		}
+	}
+	c.Header("Link", strings.Join(links, ", "))
+}
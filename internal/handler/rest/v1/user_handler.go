@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/capigiba/capiary/internal/domain/constant"
 	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/domain/request"
+	"github.com/capigiba/capiary/internal/infra/db/query"
+	"github.com/capigiba/capiary/internal/middleware"
 	"github.com/capigiba/capiary/internal/services"
 	"github.com/gin-gonic/gin"
 )
@@ -87,3 +92,179 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
 }
+
+// FindUsersHandler lists users for admins with the same filter/where/sort/fields
+// and page or cursor parameters as the other list endpoints.
+// e.g. GET /users/list?filter=status__==__pending&sort=created_at__desc
+func (h *UserHandler) FindUsersHandler(c *gin.Context) {
+	rawFilters := c.QueryArray("filter")
+	rawWhere := c.Query("where")
+	rawSorts := c.QueryArray("sort")
+	rawFields := c.Query("fields")
+	cursor := c.Query("cursor")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	users, pageInfo, err := h.userService.FindUsers(c.Request.Context(), rawFilters, rawWhere, rawSorts, rawFields, page, pageSize, cursor, wantsTotal(c))
+	if err != nil {
+		respondUserError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": users,
+		"meta": listMeta(c, page, pageSize, len(users), pageInfo),
+	})
+}
+
+// GetUserHandler returns one user, without the password hash.
+// e.g. GET /users/:user_id
+func (h *UserHandler) GetUserHandler(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		respondUserError(c, err, http.StatusInternalServerError)
+		return
+	}
+	if user == nil {
+		respondUserError(c, services.ErrUserNotFound, http.StatusNotFound)
+		return
+	}
+	user.Password = ""
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// ModerateUserHandler returns the handler for one moderation action: approve,
+// suspend, ban or reinstate. Suspend and ban take {"reason": "..."}.
+// e.g. POST /users/:user_id/suspend
+func (h *UserHandler) ModerateUserHandler(action constant.UserAuditAction) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := userIDParam(c)
+		if !ok {
+			return
+		}
+
+		var req request.ModerateUserRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON: " + err.Error()})
+				return
+			}
+		}
+
+		user, err := h.userService.ModerateUser(c.Request.Context(), middleware.CurrentUser(c), userID, action, req.Reason)
+		if err != nil {
+			respondUserError(c, err, http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": user})
+	}
+}
+
+// ChangeUserRoleHandler gives a user a new role.
+// e.g. PUT /users/:user_id/role {"role": "cj"}
+func (h *UserHandler) ChangeUserRoleHandler(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req request.ChangeUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON: " + err.Error()})
+		return
+	}
+
+	user, err := h.userService.ChangeUserRole(c.Request.Context(), middleware.CurrentUser(c), userID, req.Role)
+	if err != nil {
+		respondUserError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+// DeleteUserHandler soft-deletes a user, or removes the row when hard=true.
+// e.g. DELETE /users/:user_id?hard=true&reason=spam
+func (h *UserHandler) DeleteUserHandler(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	hard, _ := strconv.ParseBool(c.DefaultQuery("hard", "false"))
+
+	err := h.userService.DeleteUser(c.Request.Context(), middleware.CurrentUser(c), userID, hard, c.Query("reason"))
+	if err != nil {
+		respondUserError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "user deleted", "hard": hard})
+}
+
+// UserAuditHandler lists the admin actions taken on a user, newest first.
+// e.g. GET /users/:user_id/audit?page_size=20
+func (h *UserHandler) UserAuditHandler(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	entries, pageInfo, err := h.userService.ListUserAudit(c.Request.Context(), userID, page, pageSize, c.Query("cursor"), wantsTotal(c))
+	if err != nil {
+		respondUserError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": entries,
+		"meta": listMeta(c, page, pageSize, len(entries), pageInfo),
+	})
+}
+
+// userIDParam parses :user_id, answering 400 itself when it is not a number.
+func userIDParam(c *gin.Context) (uint64, bool) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// respondUserError maps the service's sentinel errors to HTTP statuses,
+// falling back to the given status for anything else.
+func respondUserError(c *gin.Context, err error, fallback int) {
+	status := fallback
+	var fieldErr *query.FieldError
+	switch {
+	case errors.As(err, &fieldErr), errors.Is(err, services.ErrInvalidCursor):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrInvalidRole), errors.Is(err, services.ErrReasonRequired):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrUserNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrSelfModeration):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrInvalidAccountTransition):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
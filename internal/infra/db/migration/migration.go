@@ -0,0 +1,200 @@
+// Package migration applies ordered, versioned schema changes to the
+// databases the service uses, and records which ones have run.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// StateName is the table or collection where each database records its
+// applied migrations.
+const StateName = "schema_migrations"
+
+// Direction says whether a migration is being applied or rolled back.
+type Direction string
+
+const (
+	Up   Direction = "up"
+	Down Direction = "down"
+)
+
+// Migration describes one change. Versions order migrations within a source;
+// they are timestamps such as 20261017000000.
+type Migration struct {
+	Version      int64
+	Name         string
+	Irreversible bool // backfills and the like; rolling back stops here
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// Source is one database with its own migrations and state.
+type Source interface {
+	Name() string
+	// Migrations returns every known migration, sorted by version.
+	Migrations() []Migration
+	// Applied returns the applied versions with the time each was applied.
+	Applied(ctx context.Context) (map[int64]time.Time, error)
+	// Apply runs one migration in the given direction and records the result.
+	Apply(ctx context.Context, m Migration, dir Direction) error
+	// Plan describes what Apply would do, for dry runs.
+	Plan(m Migration, dir Direction) string
+}
+
+// Runner drives status, up, down and redo across sources. With DryRun set it
+// prints the plan and changes nothing.
+type Runner struct {
+	sources []Source
+	out     io.Writer
+	dryRun  bool
+}
+
+func NewRunner(out io.Writer, dryRun bool, sources ...Source) *Runner {
+	return &Runner{sources: sources, out: out, dryRun: dryRun}
+}
+
+// Status lists every migration of every source and whether it has run.
+// Applied versions the code no longer knows are reported too.
+func (r *Runner) Status(ctx context.Context) error {
+	for _, src := range r.sources {
+		applied, err := src.Applied(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", src.Name(), err)
+		}
+		fmt.Fprintf(r.out, "%s:\n", src.Name())
+
+		known := make(map[int64]bool)
+		for _, m := range src.Migrations() {
+			known[m.Version] = true
+			if at, ok := applied[m.Version]; ok {
+				fmt.Fprintf(r.out, "  [x] %s (applied %s)\n", m, at.Format(time.RFC3339))
+			} else {
+				fmt.Fprintf(r.out, "  [ ] %s\n", m)
+			}
+		}
+		for version := range applied {
+			if !known[version] {
+				fmt.Fprintf(r.out, "  [?] %d (applied, but unknown to this build)\n", version)
+			}
+		}
+	}
+	return nil
+}
+
+// Up applies every pending migration, source by source, in version order.
+func (r *Runner) Up(ctx context.Context) error {
+	for _, src := range r.sources {
+		applied, err := src.Applied(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", src.Name(), err)
+		}
+		pending := 0
+		for _, m := range src.Migrations() {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			pending++
+			if err := r.apply(ctx, src, m, Up); err != nil {
+				return err
+			}
+		}
+		if pending == 0 {
+			fmt.Fprintf(r.out, "%s: up to date\n", src.Name())
+		}
+	}
+	return nil
+}
+
+// Down rolls back the most recently applied migrations, newest first.
+func (r *Runner) Down(ctx context.Context, steps int) error {
+	latest, err := r.latest(ctx, steps)
+	if err != nil {
+		return err
+	}
+	if len(latest) == 0 {
+		fmt.Fprintln(r.out, "nothing to roll back")
+		return nil
+	}
+	for _, a := range latest {
+		if a.migration.Irreversible {
+			return fmt.Errorf("%s: %s cannot be rolled back", a.source.Name(), a.migration)
+		}
+	}
+	for _, a := range latest {
+		if err := r.apply(ctx, a.source, a.migration, Down); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Redo rolls back the most recently applied migration and applies it again.
+func (r *Runner) Redo(ctx context.Context) error {
+	latest, err := r.latest(ctx, 1)
+	if err != nil {
+		return err
+	}
+	if len(latest) == 0 {
+		fmt.Fprintln(r.out, "nothing to redo")
+		return nil
+	}
+	a := latest[0]
+	if a.migration.Irreversible {
+		return fmt.Errorf("%s: %s cannot be rolled back", a.source.Name(), a.migration)
+	}
+	if err := r.apply(ctx, a.source, a.migration, Down); err != nil {
+		return err
+	}
+	return r.apply(ctx, a.source, a.migration, Up)
+}
+
+func (r *Runner) apply(ctx context.Context, src Source, m Migration, dir Direction) error {
+	if r.dryRun {
+		fmt.Fprintf(r.out, "%s: would migrate %s %s\n%s\n", src.Name(), dir, m, src.Plan(m, dir))
+		return nil
+	}
+	fmt.Fprintf(r.out, "%s: migrating %s %s\n", src.Name(), dir, m)
+	if err := src.Apply(ctx, m, dir); err != nil {
+		return fmt.Errorf("%s: %s %s: %w", src.Name(), dir, m, err)
+	}
+	return nil
+}
+
+type appliedMigration struct {
+	source    Source
+	migration Migration
+	at        time.Time
+}
+
+// latest returns up to n applied migrations across all sources, most
+// recently applied first. Ties, as within one Up run, fall back to version.
+func (r *Runner) latest(ctx context.Context, n int) ([]appliedMigration, error) {
+	var all []appliedMigration
+	for _, src := range r.sources {
+		applied, err := src.Applied(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src.Name(), err)
+		}
+		for _, m := range src.Migrations() {
+			if at, ok := applied[m.Version]; ok {
+				all = append(all, appliedMigration{source: src, migration: m, at: at})
+			}
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if !all[i].at.Equal(all[j].at) {
+			return all[i].at.After(all[j].at)
+		}
+		return all[i].migration.Version > all[j].migration.Version
+	})
+	if len(all) > n {
+		all = all[:n]
+	}
+	return all, nil
+}
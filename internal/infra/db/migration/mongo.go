@@ -0,0 +1,93 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoMigration is a migration written in Go against a database. A nil Down
+// makes it irreversible.
+type MongoMigration struct {
+	Version     int64
+	Name        string
+	Description string // what a dry run prints
+	Up          func(ctx context.Context, db *mongo.Database) error
+	Down        func(ctx context.Context, db *mongo.Database) error
+}
+
+// MongoSource applies MongoMigrations and records them in a collection.
+// MongoDB cannot wrap collMod or index builds in a transaction, so a
+// migration that fails half way must be safe to run again.
+type MongoSource struct {
+	db         *mongo.Database
+	migrations map[int64]MongoMigration
+	ordered    []Migration
+}
+
+func NewMongoSource(db *mongo.Database, migrations ...MongoMigration) *MongoSource {
+	src := &MongoSource{db: db, migrations: make(map[int64]MongoMigration, len(migrations))}
+	for _, m := range migrations {
+		src.migrations[m.Version] = m
+		src.ordered = append(src.ordered, Migration{Version: m.Version, Name: m.Name, Irreversible: m.Down == nil})
+	}
+	sort.Slice(src.ordered, func(i, j int) bool { return src.ordered[i].Version < src.ordered[j].Version })
+	return src
+}
+
+func (s *MongoSource) Name() string { return "mongodb" }
+
+func (s *MongoSource) Migrations() []Migration { return s.ordered }
+
+type mongoState struct {
+	Version   int64     `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+func (s *MongoSource) Applied(ctx context.Context) (map[int64]time.Time, error) {
+	cursor, err := s.db.Collection(StateName).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", StateName, err)
+	}
+	var states []mongoState
+	if err := cursor.All(ctx, &states); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", StateName, err)
+	}
+	applied := make(map[int64]time.Time, len(states))
+	for _, state := range states {
+		applied[state.Version] = state.AppliedAt
+	}
+	return applied, nil
+}
+
+func (s *MongoSource) Apply(ctx context.Context, m Migration, dir Direction) error {
+	migration := s.migrations[m.Version]
+	state := s.db.Collection(StateName)
+
+	if dir == Down {
+		if err := migration.Down(ctx, s.db); err != nil {
+			return err
+		}
+		if _, err := state.DeleteOne(ctx, bson.M{"_id": m.Version}); err != nil {
+			return fmt.Errorf("failed to record migration: %w", err)
+		}
+		return nil
+	}
+
+	if err := migration.Up(ctx, s.db); err != nil {
+		return err
+	}
+	if _, err := state.InsertOne(ctx, mongoState{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}); err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+	return nil
+}
+
+func (s *MongoSource) Plan(m Migration, dir Direction) string {
+	return fmt.Sprintf("  %s (%s)", s.migrations[m.Version].Description, dir)
+}
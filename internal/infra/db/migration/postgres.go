@@ -0,0 +1,148 @@
+package migration
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// postgresFiles holds the Postgres migrations as <version>_<name>.up.sql
+// with a matching .down.sql. A migration without a down file is irreversible.
+//
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+type postgresScript struct {
+	up, down string
+}
+
+// PostgresSource applies the embedded SQL migrations. Each migration runs in
+// a transaction together with its state row, so a failure leaves no trace.
+type PostgresSource struct {
+	db         *sqlx.DB
+	migrations []Migration
+	scripts    map[int64]postgresScript
+}
+
+func NewPostgresSource(db *sqlx.DB) (*PostgresSource, error) {
+	entries, err := postgresFiles.ReadDir("postgres")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read postgres migrations: %w", err)
+	}
+
+	src := &PostgresSource{db: db, scripts: make(map[int64]postgresScript)}
+	names := make(map[int64]string)
+	for _, entry := range entries {
+		base, dir, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		version, name, okName := strings.Cut(base, "_")
+		v, err := strconv.ParseInt(version, 10, 64)
+		if !ok || !okName || err != nil || (dir != string(Up) && dir != string(Down)) {
+			return nil, fmt.Errorf("unexpected migration file name %q", entry.Name())
+		}
+		body, err := postgresFiles.ReadFile(path.Join("postgres", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		script := src.scripts[v]
+		if dir == string(Up) {
+			script.up = string(body)
+		} else {
+			script.down = string(body)
+		}
+		src.scripts[v] = script
+		names[v] = name
+	}
+
+	for v, script := range src.scripts {
+		if script.up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", v, names[v])
+		}
+		src.migrations = append(src.migrations, Migration{Version: v, Name: names[v], Irreversible: script.down == ""})
+	}
+	sort.Slice(src.migrations, func(i, j int) bool { return src.migrations[i].Version < src.migrations[j].Version })
+	return src, nil
+}
+
+func (s *PostgresSource) Name() string { return "postgres" }
+
+func (s *PostgresSource) Migrations() []Migration { return s.migrations }
+
+// Applied reads the state table. A database that has never been migrated has
+// no table yet, which Applied treats as nothing applied rather than creating
+// it, so status and dry runs stay read-only.
+func (s *PostgresSource) Applied(ctx context.Context) (map[int64]time.Time, error) {
+	var exists bool
+	if err := s.db.GetContext(ctx, &exists, "SELECT to_regclass($1) IS NOT NULL", StateName); err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", StateName, err)
+	}
+	applied := make(map[int64]time.Time)
+	if !exists {
+		return applied, nil
+	}
+
+	var rows []struct {
+		Version   int64     `db:"version"`
+		AppliedAt time.Time `db:"applied_at"`
+	}
+	stmt := fmt.Sprintf("SELECT version, applied_at FROM %s", pq.QuoteIdentifier(StateName))
+	if err := s.db.SelectContext(ctx, &rows, stmt); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", StateName, err)
+	}
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+func (s *PostgresSource) Apply(ctx context.Context, m Migration, dir Direction) error {
+	state := pq.QuoteIdentifier(StateName)
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`, state)); err != nil {
+		return fmt.Errorf("failed to create %s: %w", StateName, err)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	script := s.scripts[m.Version]
+	body := script.up
+	if dir == Down {
+		body = script.down
+	}
+	if _, err := tx.ExecContext(ctx, body); err != nil {
+		return err
+	}
+
+	if dir == Up {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (version, name) VALUES ($1, $2)", state), m.Version, m.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE version = $1", state), m.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Plan returns the SQL Apply would run.
+func (s *PostgresSource) Plan(m Migration, dir Direction) string {
+	if dir == Down {
+		return s.scripts[m.Version].down
+	}
+	return s.scripts[m.Version].up
+}
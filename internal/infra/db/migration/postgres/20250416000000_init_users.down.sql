@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS users;
+DROP TYPE IF EXISTS role;
+DROP TYPE IF EXISTS account_status;
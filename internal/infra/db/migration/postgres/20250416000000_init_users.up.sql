@@ -1,24 +1,31 @@
--- Create AccountStatus enum type
-CREATE TYPE account_status AS ENUM (
-    'active',
-    'inactive',
-    'pending',
-    'suspended',
-    'banned',
-    'deleted',
-    'archived'
-);
+-- Create AccountStatus enum type.
+-- Guarded so databases created by hand before migrations existed can adopt them.
+DO $$ BEGIN
+    CREATE TYPE account_status AS ENUM (
+        'active',
+        'inactive',
+        'pending',
+        'suspended',
+        'banned',
+        'deleted',
+        'archived'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
 
 -- Create Role enum type
-CREATE TYPE role AS ENUM (
-    'basic',
-    'premium',
-    'cj',
-    'admin'
-);
+DO $$ BEGIN
+    CREATE TYPE role AS ENUM (
+        'basic',
+        'premium',
+        'cj',
+        'admin'
+    );
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
 
 -- Create the users table with the appropriate enum types for `status` and `role`
-CREATE TABLE users (
+CREATE TABLE IF NOT EXISTS users (
     id BIGSERIAL PRIMARY KEY,
     first_name VARCHAR(255) NOT NULL,
     last_name VARCHAR(255) NOT NULL,
@@ -35,5 +42,5 @@ CREATE TABLE users (
 );
 
 -- Optionally, you can add an index to improve search performance on frequently queried fields
-CREATE INDEX idx_users_status ON users (status);
-CREATE INDEX idx_users_role ON users (role);
+CREATE INDEX IF NOT EXISTS idx_users_status ON users (status);
+CREATE INDEX IF NOT EXISTS idx_users_role ON users (role);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_users_created_at_id;
+
+ALTER TABLE users
+    ALTER COLUMN avatar DROP NOT NULL,
+    ALTER COLUMN avatar DROP DEFAULT,
+    ALTER COLUMN avatar_folder DROP NOT NULL,
+    ALTER COLUMN avatar_folder DROP DEFAULT;
@@ -0,0 +1,11 @@
+-- The user entity scans avatars into plain strings, which fails on NULL.
+UPDATE users SET avatar = '' WHERE avatar IS NULL;
+UPDATE users SET avatar_folder = '' WHERE avatar_folder IS NULL;
+ALTER TABLE users
+    ALTER COLUMN avatar SET DEFAULT '',
+    ALTER COLUMN avatar SET NOT NULL,
+    ALTER COLUMN avatar_folder SET DEFAULT '',
+    ALTER COLUMN avatar_folder SET NOT NULL;
+
+-- Backs the default newest-first listing and its keyset cursors.
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users (created_at DESC, id DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_audit_log;
+
+ALTER TABLE users DROP COLUMN IF EXISTS status_reason;
@@ -0,0 +1,18 @@
+-- Why an account was last suspended, banned or deleted; shown to the user on login.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT '';
+
+-- Append-only record of every admin action on a user account. There are no
+-- foreign keys: entries must outlive hard-deleted users.
+CREATE TABLE IF NOT EXISTS user_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT NOT NULL,
+    target_id BIGINT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    from_value VARCHAR(32) NOT NULL DEFAULT '',
+    to_value VARCHAR(32) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_audit_log_target ON user_audit_log (target_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_user_audit_log_actor ON user_audit_log (actor_id, created_at DESC, id DESC);
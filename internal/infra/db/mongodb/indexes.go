@@ -0,0 +1,305 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IndexSpec declares one index a repository needs. Name identifies the index
+// when reconciling, so renaming a spec drops nothing but creates a new index.
+type IndexSpec struct {
+	Name          string
+	Keys          bson.D // direction 1/-1, or "text" for text indexes
+	Unique        bool
+	ExpireAfter   time.Duration // TTL on a date field; zero means no expiry
+	PartialFilter bson.M
+	Weights       bson.M // text index field weights; unlisted text fields weigh 1
+}
+
+func (s IndexSpec) model() mongo.IndexModel {
+	opts := options.Index().SetName(s.Name)
+	if s.Unique {
+		opts.SetUnique(true)
+	}
+	if s.ExpireAfter > 0 {
+		opts.SetExpireAfterSeconds(int32(s.ExpireAfter / time.Second))
+	}
+	if s.PartialFilter != nil {
+		opts.SetPartialFilterExpression(s.PartialFilter)
+	}
+	if s.Weights != nil {
+		opts.SetWeights(s.Weights)
+	}
+	return mongo.IndexModel{Keys: s.Keys, Options: opts}
+}
+
+// definition renders the spec the same way describeIndex renders an index
+// reported by the server, so the two can be compared as strings.
+func (s IndexSpec) definition() string {
+	var text bson.D
+	var keys bson.D
+	for _, key := range s.Keys {
+		if key.Value == "text" {
+			weight := interface{}(1)
+			if w, ok := s.Weights[key.Key]; ok {
+				weight = w
+			}
+			text = append(text, bson.E{Key: key.Key, Value: weight})
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	var partial interface{}
+	if s.PartialFilter != nil {
+		partial = s.PartialFilter
+	}
+	var ttl interface{}
+	if s.ExpireAfter > 0 {
+		ttl = int64(s.ExpireAfter / time.Second)
+	}
+	return describeIndex(keys, text, s.Unique, ttl, partial)
+}
+
+type indexInfo struct {
+	Name                    string      `bson:"name"`
+	Key                     bson.D      `bson:"key"`
+	Unique                  bool        `bson:"unique"`
+	ExpireAfterSeconds      interface{} `bson:"expireAfterSeconds"`
+	PartialFilterExpression interface{} `bson:"partialFilterExpression"`
+	Weights                 bson.D      `bson:"weights"`
+}
+
+func (i indexInfo) definition() string {
+	// The server reports text indexes as {_fts: "text", _ftsx: 1} plus weights;
+	// any other key parts are regular prefix or suffix fields.
+	var keys bson.D
+	for _, key := range i.Key {
+		if key.Key == "_fts" || key.Key == "_ftsx" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return describeIndex(keys, i.Weights, i.Unique, i.ExpireAfterSeconds, i.PartialFilterExpression)
+}
+
+func describeIndex(keys, text bson.D, unique bool, ttl, partial interface{}) string {
+	var b strings.Builder
+	b.WriteString(canonical(keys))
+	if len(text) > 0 {
+		sorted := append(bson.D{}, text...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+		b.WriteString(" text=" + canonical(sorted))
+	}
+	if unique {
+		b.WriteString(" unique")
+	}
+	if ttl != nil {
+		b.WriteString(" ttl=" + canonical(ttl) + "s")
+	}
+	if partial != nil {
+		b.WriteString(" partial=" + canonical(unordered(partial)))
+	}
+	return b.String()
+}
+
+// unordered turns documents into maps, for values like filter expressions
+// whose key order carries no meaning.
+func unordered(v interface{}) interface{} {
+	switch val := v.(type) {
+	case bson.D:
+		m := make(bson.M, len(val))
+		for _, e := range val {
+			m[e.Key] = unordered(e.Value)
+		}
+		return m
+	case bson.M:
+		m := make(bson.M, len(val))
+		for k, e := range val {
+			m[k] = unordered(e)
+		}
+		return m
+	case bson.A:
+		a := make(bson.A, len(val))
+		for i, e := range val {
+			a[i] = unordered(e)
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+// canonical prints a BSON value with map keys sorted and every number in one
+// form, since the server may hand back an int32 where we declared an int.
+func canonical(v interface{}) string {
+	switch val := v.(type) {
+	case bson.D:
+		parts := make([]string, 0, len(val))
+		for _, e := range val {
+			parts = append(parts, e.Key+": "+canonical(e.Value))
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case bson.M:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, k+": "+canonical(val[k]))
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case bson.A:
+		parts := make([]string, 0, len(val))
+		for _, e := range val {
+			parts = append(parts, canonical(e))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case []interface{}:
+		return canonical(bson.A(val))
+	case int:
+		return canonicalNumber(float64(val))
+	case int32:
+		return canonicalNumber(float64(val))
+	case int64:
+		return canonicalNumber(float64(val))
+	case float64:
+		return canonicalNumber(val)
+	case string:
+		return fmt.Sprintf("%q", val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+func canonicalNumber(f float64) string {
+	if f == math.Trunc(f) {
+		return fmt.Sprintf("%d", int64(f))
+	}
+	return fmt.Sprintf("%g", f)
+}
+
+// IndexChange is a declared index whose definition differs from the one on the server.
+type IndexChange struct {
+	Name     string
+	Declared string
+	Actual   string
+}
+
+// IndexDiff compares a collection's declared indexes with the ones that exist.
+type IndexDiff struct {
+	Collection string
+	Missing    []IndexSpec
+	Changed    []IndexChange
+	Extra      []string // present on the server but not declared; never dropped automatically
+}
+
+// Empty reports whether the collection matches its declaration.
+func (d IndexDiff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Changed) == 0 && len(d.Extra) == 0
+}
+
+func (d IndexDiff) String() string {
+	if d.Empty() {
+		return d.Collection + ": in sync\n"
+	}
+	var b strings.Builder
+	b.WriteString(d.Collection + ":\n")
+	for _, spec := range d.Missing {
+		fmt.Fprintf(&b, "  + %s %s\n", spec.Name, spec.definition())
+	}
+	for _, change := range d.Changed {
+		fmt.Fprintf(&b, "  ~ %s\n      actual:   %s\n      declared: %s\n", change.Name, change.Actual, change.Declared)
+	}
+	for _, name := range d.Extra {
+		fmt.Fprintf(&b, "  ? %s (not declared)\n", name)
+	}
+	return b.String()
+}
+
+// WithIndexes declares the indexes the collection needs. They are created by
+// EnsureIndexes, which the server runs once at startup.
+func (m *MongoDBAdapter[T]) WithIndexes(specs ...IndexSpec) *MongoDBAdapter[T] {
+	m.indexes = append(m.indexes, specs...)
+	return m
+}
+
+// DiffIndexes compares the declared indexes with the ones on the server.
+func (m *MongoDBAdapter[T]) DiffIndexes(ctx context.Context) (IndexDiff, error) {
+	diff := IndexDiff{Collection: m.collection.Name()}
+
+	cursor, err := m.collection.Indexes().List(ctx)
+	if err != nil {
+		return diff, fmt.Errorf("failed to list indexes on %s: %v", diff.Collection, err)
+	}
+	var existing []indexInfo
+	if err := cursor.All(ctx, &existing); err != nil {
+		return diff, fmt.Errorf("failed to decode indexes on %s: %v", diff.Collection, err)
+	}
+
+	actual := make(map[string]indexInfo, len(existing))
+	for _, info := range existing {
+		actual[info.Name] = info
+	}
+
+	declared := make(map[string]bool, len(m.indexes))
+	for _, spec := range m.indexes {
+		declared[spec.Name] = true
+		info, ok := actual[spec.Name]
+		if !ok {
+			diff.Missing = append(diff.Missing, spec)
+			continue
+		}
+		if want, got := spec.definition(), info.definition(); want != got {
+			diff.Changed = append(diff.Changed, IndexChange{Name: spec.Name, Declared: want, Actual: got})
+		}
+	}
+	for _, info := range existing {
+		if info.Name != "_id_" && !declared[info.Name] {
+			diff.Extra = append(diff.Extra, info.Name)
+		}
+	}
+	return diff, nil
+}
+
+// EnsureIndexes reconciles the collection with its declaration: missing
+// indexes are created and changed ones are dropped and rebuilt. Undeclared
+// indexes are left alone and only reported. It returns the diff it acted on.
+func (m *MongoDBAdapter[T]) EnsureIndexes(ctx context.Context) (IndexDiff, error) {
+	diff, err := m.DiffIndexes(ctx)
+	if err != nil {
+		return diff, err
+	}
+
+	var models []mongo.IndexModel
+	for _, spec := range diff.Missing {
+		models = append(models, spec.model())
+	}
+	for _, change := range diff.Changed {
+		if _, err := m.collection.Indexes().DropOne(ctx, change.Name); err != nil {
+			return diff, fmt.Errorf("failed to drop index %s on %s: %v", change.Name, diff.Collection, err)
+		}
+		for _, spec := range m.indexes {
+			if spec.Name == change.Name {
+				models = append(models, spec.model())
+			}
+		}
+	}
+	if len(models) == 0 {
+		return diff, nil
+	}
+	if _, err := m.collection.Indexes().CreateMany(ctx, models); err != nil {
+		return diff, fmt.Errorf("failed to create indexes on %s: %v", diff.Collection, err)
+	}
+	return diff, nil
+}
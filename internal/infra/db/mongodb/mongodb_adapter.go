@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/capigiba/capiary/internal/infra/db/query"
@@ -11,9 +12,13 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrDuplicateKey is returned, wrapped, when a write violates a unique index.
+var ErrDuplicateKey = errors.New("duplicate key")
+
 type MongoDBAdapter[T any] struct {
 	collection *mongo.Collection
 	ctx        context.Context
+	indexes    []IndexSpec
 }
 
 func NewMongoDBAdapter[T any](client *mongo.Client, databaseName, collectionName string) *MongoDBAdapter[T] {
@@ -75,12 +80,56 @@ func (m *MongoDBAdapter[T]) Find(filter interface{}, opts ...*options.FindOption
 	return results, nil
 }
 
-func (m *MongoDBAdapter[T]) UpdateOne(filter, update interface{}) error {
-	_, err := m.collection.UpdateOne(m.ctx, filter, bson.M{"$set": update})
+// UpdateOne applies a $set to the first document matching filter and reports
+// how many documents matched, so callers can detect a filter that no longer applies.
+func (m *MongoDBAdapter[T]) UpdateOne(filter, update interface{}) (int64, error) {
+	result, err := m.collection.UpdateOne(m.ctx, filter, bson.M{"$set": update})
 	if err != nil {
-		return fmt.Errorf("failed to update document: %v", err)
+		return 0, writeError("failed to update document", err)
 	}
-	return nil
+	return result.MatchedCount, nil
+}
+
+// FindOneAndUpdate atomically applies a $set to the first document matching
+// filter and returns the updated document, or nil if nothing matched.
+func (m *MongoDBAdapter[T]) FindOneAndUpdate(filter, update interface{}, opts ...*options.FindOneAndUpdateOptions) (*T, error) {
+	opts = append([]*options.FindOneAndUpdateOptions{options.FindOneAndUpdate().SetReturnDocument(options.After)}, opts...)
+
+	var result T
+	err := m.collection.FindOneAndUpdate(m.ctx, filter, bson.M{"$set": update}, opts...).Decode(&result)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, writeError("failed to find and update document", err)
+	}
+	return &result, nil
+}
+
+// UpdateMany applies a full update document (e.g. {"$pull": ...}) to every
+// document matching filter and reports how many were modified.
+func (m *MongoDBAdapter[T]) UpdateMany(filter, update interface{}) (int64, error) {
+	result, err := m.collection.UpdateMany(m.ctx, filter, update)
+	if err != nil {
+		return 0, writeError("failed to update documents", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (m *MongoDBAdapter[T]) CountDocuments(filter interface{}) (int64, error) {
+	count, err := m.collection.CountDocuments(m.ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count documents: %v", err)
+	}
+	return count, nil
+}
+
+func (m *MongoDBAdapter[T]) DeleteOne(filter interface{}) (int64, error) {
+	result, err := m.collection.DeleteOne(m.ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete document: %v", err)
+	}
+	return result.DeletedCount, nil
 }
 
 func (m *MongoDBAdapter[T]) BulkWrite(data map[string]T) error {
@@ -96,7 +145,7 @@ func (m *MongoDBAdapter[T]) BulkWrite(data map[string]T) error {
 
 	_, err := m.collection.BulkWrite(m.ctx, operations)
 	if err != nil {
-		return fmt.Errorf("could not perform bulk write: %v", err)
+		return writeError("could not perform bulk write", err)
 	}
 
 	return nil
@@ -105,7 +154,7 @@ func (m *MongoDBAdapter[T]) BulkWrite(data map[string]T) error {
 func (m *MongoDBAdapter[T]) InsertOne(data T) (primitive.ObjectID, error) {
 	result, err := m.collection.InsertOne(m.ctx, data)
 	if err != nil {
-		return primitive.NilObjectID, fmt.Errorf("failed to insert document: %v", err)
+		return primitive.NilObjectID, writeError("failed to insert document", err)
 	}
 
 	oid, ok := result.InsertedID.(primitive.ObjectID)
@@ -115,3 +164,11 @@ func (m *MongoDBAdapter[T]) InsertOne(data T) (primitive.ObjectID, error) {
 
 	return oid, nil
 }
+
+// writeError keeps unique index violations recognizable with errors.Is(err, ErrDuplicateKey).
+func writeError(msg string, err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%s: %w: %v", msg, ErrDuplicateKey, err)
+	}
+	return fmt.Errorf("%s: %v", msg, err)
+}
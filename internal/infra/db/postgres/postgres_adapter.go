@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/capigiba/capiary/internal/infra/db/query"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ErrDuplicateKey is returned, wrapped, when a write violates a unique constraint.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// PostgresAdapter is the Postgres counterpart of mongodb.MongoDBAdapter. The
+// table's columns are read from the db tags of T and double as the allowlist
+// for identifiers in queries.
+type PostgresAdapter[T any] struct {
+	db      *sqlx.DB
+	table   *query.PostgresTable
+	columns []string
+	key     string
+
+	softDeleteColumn string
+	softDeleteValue  interface{}
+}
+
+// NewPostgresAdapter maps T onto tableName. key is the primary key column,
+// which the database assigns on insert.
+func NewPostgresAdapter[T any](db *sqlx.DB, tableName, key string) *PostgresAdapter[T] {
+	columns := dbColumns(reflect.TypeOf((*T)(nil)).Elem())
+	return &PostgresAdapter[T]{
+		db:      db,
+		table:   query.NewPostgresTable(tableName, columns...),
+		columns: columns,
+		key:     key,
+	}
+}
+
+// WithSoftDelete makes SoftDelete mark rows by setting column to value
+// instead of removing them.
+func (p *PostgresAdapter[T]) WithSoftDelete(column string, value interface{}) *PostgresAdapter[T] {
+	p.softDeleteColumn = column
+	p.softDeleteValue = value
+	return p
+}
+
+// ╔═════════════════════════════════════════╗
+// ║     FindWithQuery using Query           ║
+// ╚═════════════════════════════════════════╝
+func (p *PostgresAdapter[T]) FindWithQuery(ctx context.Context, opts query.QueryOptions) ([]T, error) {
+	// Name the mapped columns rather than "*", so columns T does not know
+	// about cannot break scanning.
+	if len(opts.Fields) == 0 {
+		opts.Fields = p.columns
+	}
+	stmt, args, err := query.BuildPostgresSelectQuery(p.table, opts)
+	if err != nil {
+		return nil, err
+	}
+	var results []T
+	if err := sqlx.SelectContext(ctx, conn(ctx, p.db), &results, stmt, args...); err != nil {
+		return nil, fmt.Errorf("failed to find rows: %v", err)
+	}
+	return results, nil
+}
+
+// ╔═════════════════════════════════════════╗
+// ║         Common Postgres Functions       ║
+// ╚═════════════════════════════════════════╝
+
+// FindOne returns the first row matching filters, or nil if none does.
+func (p *PostgresAdapter[T]) FindOne(ctx context.Context, filters ...query.Filter) (*T, error) {
+	stmt, args, err := query.BuildPostgresSelectQuery(p.table, query.QueryOptions{Filters: filters, Fields: p.columns, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	var result T
+	err = sqlx.GetContext(ctx, conn(ctx, p.db), &result, stmt, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find one row: %v", err)
+	}
+	return &result, nil
+}
+
+// Insert writes every column of data except the key and returns the stored
+// row, including the assigned key and any column defaults.
+func (p *PostgresAdapter[T]) Insert(ctx context.Context, data T) (*T, error) {
+	v := reflect.ValueOf(data)
+	var (
+		columns      []string
+		returning    []string
+		placeholders []string
+		args         []interface{}
+	)
+	for _, name := range p.columns {
+		column, err := p.table.Column(name)
+		if err != nil {
+			return nil, err
+		}
+		returning = append(returning, column)
+		if name == p.key {
+			continue
+		}
+		columns = append(columns, column)
+		args = append(args, fieldByColumn(v, name))
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s",
+		p.table.Name(), strings.Join(columns, ", "), strings.Join(placeholders, ", "), strings.Join(returning, ", "))
+	var result T
+	if err := sqlx.GetContext(ctx, conn(ctx, p.db), &result, stmt, args...); err != nil {
+		return nil, writeError("failed to insert row", err)
+	}
+	return &result, nil
+}
+
+// UpdateFields sets the given columns on every row matching filters and
+// reports how many rows matched. At least one filter is required, so a
+// missing filter cannot rewrite the whole table.
+func (p *PostgresAdapter[T]) UpdateFields(ctx context.Context, fields map[string]interface{}, filters ...query.Filter) (int64, error) {
+	if len(filters) == 0 {
+		return 0, fmt.Errorf("refusing to update without a filter")
+	}
+	stmt, args, err := query.BuildPostgresUpdateQuery(p.table, fields, query.QueryOptions{Filters: filters})
+	if err != nil {
+		return 0, err
+	}
+	result, err := conn(ctx, p.db).ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return 0, writeError("failed to update rows", err)
+	}
+	return result.RowsAffected()
+}
+
+// SoftDelete marks the matching rows as configured by WithSoftDelete.
+func (p *PostgresAdapter[T]) SoftDelete(ctx context.Context, filters ...query.Filter) (int64, error) {
+	if p.softDeleteColumn == "" {
+		return 0, fmt.Errorf("soft delete is not configured for %s", p.table.Name())
+	}
+	return p.UpdateFields(ctx, map[string]interface{}{p.softDeleteColumn: p.softDeleteValue}, filters...)
+}
+
+// Delete permanently removes the matching rows.
+func (p *PostgresAdapter[T]) Delete(ctx context.Context, filters ...query.Filter) (int64, error) {
+	if len(filters) == 0 {
+		return 0, fmt.Errorf("refusing to delete without a filter")
+	}
+	stmt, args, err := query.BuildPostgresDeleteQuery(p.table, query.QueryOptions{Filters: filters})
+	if err != nil {
+		return 0, err
+	}
+	result, err := conn(ctx, p.db).ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete rows: %v", err)
+	}
+	return result.RowsAffected()
+}
+
+// Count returns how many rows match the filters and where-expression of opts.
+func (p *PostgresAdapter[T]) Count(ctx context.Context, opts query.QueryOptions) (int64, error) {
+	stmt, args, err := query.BuildPostgresCountQuery(p.table, opts)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := sqlx.GetContext(ctx, conn(ctx, p.db), &count, stmt, args...); err != nil {
+		return 0, fmt.Errorf("failed to count rows: %v", err)
+	}
+	return count, nil
+}
+
+// dbColumns lists the db tags of a struct type, in field order.
+func dbColumns(t reflect.Type) []string {
+	var columns []string
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("db"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		columns = append(columns, name)
+	}
+	return columns
+}
+
+func fieldByColumn(v reflect.Value, column string) interface{} {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("db"), ",")[0] == column {
+			return v.Field(i).Interface()
+		}
+	}
+	return nil
+}
+
+// writeError keeps unique constraint violations recognizable with errors.Is(err, ErrDuplicateKey).
+func writeError(msg string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return fmt.Errorf("%s: %w: %v", msg, ErrDuplicateKey, err)
+	}
+	return fmt.Errorf("%s: %v", msg, err)
+}
@@ -2,6 +2,8 @@ package query
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,26 +16,29 @@ func BuildMongoQuery(opts QueryOptions) (bson.M, *options.FindOptions) {
 	filter := bson.M{}
 	findOpts := options.Find()
 
+	// Each field's first filter goes straight into the document. Later ones
+	// on the same field are ANDed, so none can overwrite an earlier operator:
+	// every filter narrows the result, as in BuildPostgresSelectQuery.
+	var and bson.A
 	for _, f := range opts.Filters {
-		op := map[OperationType]string{
-			OpEqual:       "$eq",
-			OpNotEqual:    "$ne",
-			OpGreaterThan: "$gt",
-			OpLessThan:    "$lt",
-			OpGTE:         "$gte",
-			OpLTE:         "$lte",
-		}[f.Operator]
-
-		if f.Operator == OpEqual {
-			filter[f.Field] = f.Value
+		if _, exists := filter[f.Field]; exists {
+			and = append(and, mongoExpression(Condition{Filter: &f}))
 			continue
 		}
+		filter[f.Field] = mongoExpression(Condition{Filter: &f})[f.Field]
+	}
+	if opts.Where != nil {
+		and = append(and, mongoExpression(*opts.Where))
+	}
 
-		// ensure a sub‑doc exists
-		if _, ok := filter[f.Field]; !ok {
-			filter[f.Field] = bson.M{}
-		}
-		filter[f.Field].(bson.M)[op] = f.Value
+	switch {
+	case len(and) == 0:
+	case len(filter) == 0 && len(and) == 1:
+		filter = and[0].(bson.M)
+	case len(filter) == 0:
+		filter = bson.M{"$and": and}
+	default:
+		filter = bson.M{"$and": append(bson.A{filter}, and...)}
 	}
 
 	// Sort
@@ -71,3 +76,89 @@ func BuildMongoQuery(opts QueryOptions) (bson.M, *options.FindOptions) {
 
 	return filter, findOpts
 }
+
+// mongoExpression translates a boolean expression; not() becomes $nor of its
+// single child.
+func mongoExpression(cond Condition) bson.M {
+	if cond.Filter != nil {
+		if cond.Filter.Operator == OpEqual {
+			return bson.M{cond.Filter.Field: cond.Filter.Value}
+		}
+		return bson.M{cond.Filter.Field: mongoCondition(*cond.Filter)}
+	}
+
+	children := make(bson.A, 0, len(cond.Children))
+	for _, child := range cond.Children {
+		children = append(children, mongoExpression(child))
+	}
+	switch cond.Op {
+	case LogicOr:
+		return bson.M{"$or": children}
+	case LogicNot:
+		return bson.M{"$nor": children}
+	default:
+		return bson.M{"$and": children}
+	}
+}
+
+// mongoCondition translates one filter into the operators of its field's
+// sub-document. It mirrors postgresCondition so both backends agree.
+func mongoCondition(f Filter) bson.M {
+	switch f.Operator {
+	case OpNotEqual:
+		return bson.M{"$ne": f.Value}
+	case OpGreaterThan:
+		return bson.M{"$gt": f.Value}
+	case OpLessThan:
+		return bson.M{"$lt": f.Value}
+	case OpGTE:
+		return bson.M{"$gte": f.Value}
+	case OpLTE:
+		return bson.M{"$lte": f.Value}
+	case OpIn:
+		return bson.M{"$in": f.Value}
+	case OpNotIn:
+		return bson.M{"$nin": f.Value}
+	case OpAll:
+		return bson.M{"$all": f.Value}
+	case OpBetween:
+		bounds := reflect.ValueOf(f.Value)
+		return bson.M{"$gte": bounds.Index(0).Interface(), "$lte": bounds.Index(1).Interface()}
+	case OpContains:
+		return bson.M{"$regex": regexp.QuoteMeta(fmt.Sprint(f.Value)), "$options": "i"}
+	case OpRegex:
+		return bson.M{"$regex": f.Value}
+	case OpExists:
+		// $exists alone would also match explicit nulls; comparing with null
+		// matches missing and null alike, like IS NULL in SQL.
+		if exists, _ := f.Value.(bool); exists {
+			return bson.M{"$ne": nil}
+		}
+		return bson.M{"$eq": nil}
+	default:
+		return bson.M{"$eq": f.Value}
+	}
+}
+
+// MongoSortValues reads the sort-key values of a decoded document, by
+// re-encoding it and looking up each (possibly dotted) sort field.
+func MongoSortValues(doc interface{}, sorts []Sort) ([]interface{}, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	values := make([]interface{}, len(sorts))
+	for i, s := range sorts {
+		rv, err := bson.Raw(raw).LookupErr(strings.Split(s.Field, ".")...)
+		if err != nil {
+			values[i] = nil
+			continue
+		}
+		var v interface{}
+		if err := rv.Unmarshal(&v); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", s.Field, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
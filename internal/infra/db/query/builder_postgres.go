@@ -2,14 +2,47 @@ package query
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
+
+	"github.com/lib/pq"
 )
 
+// PostgresTable is the allowlist of identifiers a query may name on one
+// table. Every identifier is checked against it and quoted, so field names
+// that come from the query string never reach the SQL text unescaped.
+type PostgresTable struct {
+	name    string
+	columns map[string]bool
+}
+
+func NewPostgresTable(name string, columns ...string) *PostgresTable {
+	t := &PostgresTable{name: name, columns: make(map[string]bool, len(columns))}
+	for _, c := range columns {
+		t.columns[c] = true
+	}
+	return t
+}
+
+// Name returns the quoted table name.
+func (t *PostgresTable) Name() string {
+	return pq.QuoteIdentifier(t.name)
+}
+
+// Column returns the quoted column, or a *FieldError when the table does not allow it.
+func (t *PostgresTable) Column(name string) (string, error) {
+	if !t.columns[name] {
+		return "", &FieldError{Field: name, Reason: "unknown column on " + t.name}
+	}
+	return pq.QuoteIdentifier(name), nil
+}
+
 // BuildPostgresSelectQuery builds a SELECT query and its arguments for PostgreSQL.
-func BuildPostgresSelectQuery(tableName string, opts QueryOptions) (string, []interface{}) {
+// Identifiers are checked against the table's allowlist; values, LIMIT and
+// OFFSET are passed as placeholders.
+func BuildPostgresSelectQuery(table *PostgresTable, opts QueryOptions) (string, []interface{}, error) {
 	var (
-		args         []interface{}
-		whereClauses []string
 		selectFields string
 		orderClause  string
 	)
@@ -18,57 +51,227 @@ func BuildPostgresSelectQuery(tableName string, opts QueryOptions) (string, []in
 	if len(opts.Fields) == 0 {
 		selectFields = "*"
 	} else {
-		selectFields = strings.Join(opts.Fields, ", ")
+		columns := make([]string, 0, len(opts.Fields))
+		for _, f := range opts.Fields {
+			column, err := table.Column(f)
+			if err != nil {
+				return "", nil, err
+			}
+			columns = append(columns, column)
+		}
+		selectFields = strings.Join(columns, ", ")
 	}
 
 	// Build WHERE clauses
-	argIndex := 1
-	for _, fil := range opts.Filters {
-		var op string
-		switch fil.Operator {
-		case OpEqual:
-			op = "="
-		case OpNotEqual:
-			op = "!="
-		case OpGreaterThan:
-			op = ">"
-		case OpLessThan:
-			op = "<"
-		case OpGTE:
-			op = ">="
-		case OpLTE:
-			op = "<="
-		default:
-			// Fallback or handle custom operators here
-			op = "="
-		}
-
-		whereClauses = append(whereClauses, fmt.Sprintf("%s %s $%d", fil.Field, op, argIndex))
-		args = append(args, fil.Value)
-		argIndex++
+	whereClause, args, err := postgresWhere(table, opts, nil)
+	if err != nil {
+		return "", nil, err
 	}
 
 	// Build ORDER BY
 	if len(opts.Sorts) > 0 {
 		var sortExprs []string
 		for _, s := range opts.Sorts {
+			column, err := table.Column(s.Field)
+			if err != nil {
+				return "", nil, err
+			}
 			dir := "ASC"
 			if s.Desc {
 				dir = "DESC"
 			}
-			sortExprs = append(sortExprs, fmt.Sprintf("%s %s", s.Field, dir))
+			sortExprs = append(sortExprs, fmt.Sprintf("%s %s", column, dir))
 		}
 		orderClause = "ORDER BY " + strings.Join(sortExprs, ", ")
 	}
 
 	// Final SQL
-	query := fmt.Sprintf("SELECT %s FROM %s", selectFields, tableName)
-	if len(whereClauses) > 0 {
-		query += " WHERE " + strings.Join(whereClauses, " AND ")
-	}
+	query := fmt.Sprintf("SELECT %s FROM %s", selectFields, table.Name()) + whereClause
 	if orderClause != "" {
 		query += " " + orderClause
 	}
+	if opts.Limit > 0 {
+		args = append(args, opts.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if opts.Skip > 0 {
+		args = append(args, opts.Skip)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return query, args, nil
+}
+
+// BuildPostgresCountQuery counts the rows BuildPostgresSelectQuery would
+// return without Skip and Limit.
+func BuildPostgresCountQuery(table *PostgresTable, opts QueryOptions) (string, []interface{}, error) {
+	whereClause, args, err := postgresWhere(table, opts, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return "SELECT COUNT(*) FROM " + table.Name() + whereClause, args, nil
+}
+
+// BuildPostgresUpdateQuery sets the given columns on every row matching the
+// filters and where-expression of opts. Columns are written in sorted order
+// so the same update always renders the same SQL.
+func BuildPostgresUpdateQuery(table *PostgresTable, fields map[string]interface{}, opts QueryOptions) (string, []interface{}, error) {
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("no fields to update")
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var args []interface{}
+	sets := make([]string, 0, len(names))
+	for _, name := range names {
+		column, err := table.Column(name)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, fields[name])
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
 
-	return query, args
+	whereClause, args, err := postgresWhere(table, opts, args)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("UPDATE %s SET %s", table.Name(), strings.Join(sets, ", ")) + whereClause, args, nil
+}
+
+// BuildPostgresDeleteQuery deletes every row matching the filters and
+// where-expression of opts.
+func BuildPostgresDeleteQuery(table *PostgresTable, opts QueryOptions) (string, []interface{}, error) {
+	whereClause, args, err := postgresWhere(table, opts, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return "DELETE FROM " + table.Name() + whereClause, args, nil
+}
+
+// postgresWhere renders the filters and where-expression of opts as a
+// " WHERE ..." clause, or "" when there are none. Its placeholders continue
+// after args, which it returns extended with its own values.
+func postgresWhere(table *PostgresTable, opts QueryOptions, args []interface{}) (string, []interface{}, error) {
+	var clauses []string
+	for _, fil := range opts.Filters {
+		column, err := table.Column(fil.Field)
+		if err != nil {
+			return "", nil, err
+		}
+		clause, clauseArgs := postgresCondition(column, fil, len(args)+1)
+		clauses = append(clauses, clause)
+		args = append(args, clauseArgs...)
+	}
+	if opts.Where != nil {
+		clause, whereArgs, err := postgresExpression(table, *opts.Where, len(args)+1)
+		if err != nil {
+			return "", nil, err
+		}
+		clauses = append(clauses, clause)
+		args = append(args, whereArgs...)
+	}
+	if len(clauses) == 0 {
+		return "", args, nil
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args, nil
 }
+
+// PostgresSortValues reads the sort-key values of a scanned row by matching
+// each sort column to the struct field with that db tag.
+func PostgresSortValues(row interface{}, sorts []Sort) ([]interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(row))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct row, got %s", v.Kind())
+	}
+	values := make([]interface{}, len(sorts))
+	for i, s := range sorts {
+		for j := 0; j < v.NumField(); j++ {
+			if strings.Split(v.Type().Field(j).Tag.Get("db"), ",")[0] == s.Field {
+				values[i] = v.Field(j).Interface()
+				break
+			}
+		}
+	}
+	return values, nil
+}
+
+// postgresExpression renders a boolean expression as parenthesized SQL whose
+// placeholders start at $argIndex.
+func postgresExpression(table *PostgresTable, cond Condition, argIndex int) (string, []interface{}, error) {
+	if cond.Filter != nil {
+		column, err := table.Column(cond.Filter.Field)
+		if err != nil {
+			return "", nil, err
+		}
+		clause, args := postgresCondition(column, *cond.Filter, argIndex)
+		return clause, args, nil
+	}
+
+	var args []interface{}
+	clauses := make([]string, 0, len(cond.Children))
+	for _, child := range cond.Children {
+		clause, childArgs, err := postgresExpression(table, child, argIndex+len(args))
+		if err != nil {
+			return "", nil, err
+		}
+		clauses = append(clauses, clause)
+		args = append(args, childArgs...)
+	}
+	switch cond.Op {
+	case LogicOr:
+		return "(" + strings.Join(clauses, " OR ") + ")", args, nil
+	case LogicNot:
+		return "NOT (" + clauses[0] + ")", args, nil
+	default:
+		return "(" + strings.Join(clauses, " AND ") + ")", args, nil
+	}
+}
+
+// postgresCondition renders one filter on an already quoted column as a WHERE
+// clause whose placeholders start at $argIndex. It mirrors mongoCondition so
+// both backends agree: MongoDB's $ne and $nin also match documents where the
+// field is null or missing, so != and nin keep NULL rows too.
+func postgresCondition(column string, fil Filter, argIndex int) (string, []interface{}) {
+	placeholder := fmt.Sprintf("$%d", argIndex)
+	switch fil.Operator {
+	case OpNotEqual:
+		return fmt.Sprintf("(%s IS NULL OR %s != %s)", column, column, placeholder), []interface{}{fil.Value}
+	case OpGreaterThan:
+		return fmt.Sprintf("%s > %s", column, placeholder), []interface{}{fil.Value}
+	case OpLessThan:
+		return fmt.Sprintf("%s < %s", column, placeholder), []interface{}{fil.Value}
+	case OpGTE:
+		return fmt.Sprintf("%s >= %s", column, placeholder), []interface{}{fil.Value}
+	case OpLTE:
+		return fmt.Sprintf("%s <= %s", column, placeholder), []interface{}{fil.Value}
+	case OpIn:
+		return fmt.Sprintf("%s = ANY(%s)", column, placeholder), []interface{}{pq.Array(fil.Value)}
+	case OpNotIn:
+		return fmt.Sprintf("(%s IS NULL OR NOT (%s = ANY(%s)))", column, column, placeholder), []interface{}{pq.Array(fil.Value)}
+	case OpAll:
+		return fmt.Sprintf("%s @> %s", column, placeholder), []interface{}{pq.Array(fil.Value)}
+	case OpBetween:
+		bounds := reflect.ValueOf(fil.Value)
+		return fmt.Sprintf("%s BETWEEN %s AND $%d", column, placeholder, argIndex+1),
+			[]interface{}{bounds.Index(0).Interface(), bounds.Index(1).Interface()}
+	case OpContains:
+		return fmt.Sprintf("%s ILIKE %s", column, placeholder), []interface{}{"%" + likeEscaper.Replace(fmt.Sprint(fil.Value)) + "%"}
+	case OpRegex:
+		return fmt.Sprintf("%s ~ %s", column, placeholder), []interface{}{fil.Value}
+	case OpExists:
+		if exists, _ := fil.Value.(bool); exists {
+			return fmt.Sprintf("%s IS NOT NULL", column), nil
+		}
+		return fmt.Sprintf("%s IS NULL", column), nil
+	default:
+		return fmt.Sprintf("%s = %s", column, placeholder), []interface{}{fil.Value}
+	}
+}
+
+// likeEscaper makes LIKE wildcards in user input match literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
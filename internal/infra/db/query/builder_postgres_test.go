@@ -0,0 +1,118 @@
+package query
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// FuzzBuildPostgresSelectQuery feeds arbitrary filters, where-expressions,
+// sorts and projections through the parsers into the builder, and checks
+// that no identifier outside the table's allowlist ever reaches the SQL
+// text: every quoted identifier is an allowed column or the table, and what
+// is left outside quotes is only SQL keywords, punctuation and placeholders.
+func FuzzBuildPostgresSelectQuery(f *testing.F) {
+	table := NewPostgresTable("users", "id", "email", "created_at", `odd "column"`)
+	allowed := map[string]bool{"users": true, "id": true, "email": true, "created_at": true, `odd "column"`: true}
+
+	seeds := [][4]string{
+		{"email__==__a@example.com", "", "created_at__desc", "id,email"},
+		{"id__in__1,2,3", "or(email__contains__50%;id__between__1,9)", "id__asc", ""},
+		{`odd "column"__exists__true`, "not(email__regex__^a)", `odd "column"__asc`, `odd "column"`},
+		{`email"; DROP TABLE users; --__==__x`, "", "", ""},
+		{"email__==__'; DROP TABLE users; --", "", "", ""},
+		{"id__==__1", `and(id" OR 1=1 --__==__1)`, "", ""},
+		{"", "", `id" DESC; DELETE FROM users; --__asc`, ""},
+		{"", "", "", `id, (SELECT password FROM users)`},
+		{"", "", "", `*`},
+		{"", "", "", `email"--`},
+		{`users.email__==__x`, "", "", ""},
+		{`email\__==__x`, `email\)__==__x`, "", ""},
+	}
+	for _, s := range seeds {
+		f.Add(s[0], s[1], s[2], s[3])
+	}
+
+	f.Fuzz(func(t *testing.T, rawFilter, rawWhere, rawSort, rawFields string) {
+		var opts QueryOptions
+		if filters, err := ParseFilters([]string{rawFilter}); err == nil {
+			opts.Filters = filters
+		}
+		if where, err := ParseCondition(rawWhere); err == nil {
+			opts.Where = where
+		}
+		if sorts, err := ParseSorts([]string{rawSort}); err == nil {
+			opts.Sorts = sorts
+		}
+		opts.Fields = ParseFields(rawFields)
+		opts.Limit, opts.Skip = 10, 20
+
+		sql, args, err := BuildPostgresSelectQuery(table, opts)
+		if err != nil {
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("unexpected error type %T: %v", err, err)
+			}
+			if allowed[fieldErr.Field] {
+				t.Fatalf("allowed column %q rejected: %v", fieldErr.Field, err)
+			}
+			return
+		}
+
+		rest, identifiers := splitIdentifiers(t, sql)
+		for _, ident := range identifiers {
+			if !allowed[ident] {
+				t.Fatalf("identifier %q outside the allowlist in %q", ident, sql)
+			}
+		}
+		if !safeSQL.MatchString(rest) {
+			t.Fatalf("unexpected text outside identifiers in %q", sql)
+		}
+		for _, m := range placeholder.FindAllStringSubmatch(rest, -1) {
+			if n := len(m[1]); n == 0 || m[1][0] == '0' {
+				t.Fatalf("malformed placeholder %q in %q", m[0], sql)
+			}
+		}
+		if len(args) < 2 {
+			t.Fatalf("LIMIT and OFFSET not passed as arguments: %v", args)
+		}
+	})
+}
+
+var (
+	safeSQL     = regexp.MustCompile(`^[A-Za-z0-9_$,*()=<>!~@ ]*$`)
+	placeholder = regexp.MustCompile(`\$(\d*)`)
+)
+
+// splitIdentifiers removes the double-quoted identifiers from sql, returning
+// what is left and the unquoted identifiers.
+func splitIdentifiers(t *testing.T, sql string) (string, []string) {
+	t.Helper()
+	var rest strings.Builder
+	var identifiers []string
+	for i := 0; i < len(sql); i++ {
+		if sql[i] != '"' {
+			rest.WriteByte(sql[i])
+			continue
+		}
+		var ident strings.Builder
+		for i++; ; i++ {
+			if i >= len(sql) {
+				t.Fatalf("unterminated identifier in %q", sql)
+			}
+			if sql[i] == '"' {
+				if i+1 < len(sql) && sql[i+1] == '"' {
+					ident.WriteByte('"')
+					i++
+					continue
+				}
+				break
+			}
+			ident.WriteByte(sql[i])
+		}
+		identifiers = append(identifiers, ident.String())
+		rest.WriteString("X")
+	}
+	return rest.String(), identifiers
+}
@@ -0,0 +1,523 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The tests in this file check that BuildMongoQuery and the Postgres builder
+// agree: each query is run through an evaluator for the filter document and
+// one for the WHERE clause over the same rows, and both must return the rows
+// the case expects, listed in fixture order.
+
+type row map[string]interface{}
+
+var fixtureRows = []row{
+	{"id": int64(1), "title": "Go generics", "status": "published", "score": int64(10), "tags": []string{"go", "news"}, "note": "first"},
+	{"id": int64(2), "title": "Go modules 50% faster", "status": "draft", "score": int64(20), "tags": []string{"go"}, "note": nil},
+	{"id": int64(3), "title": "Rust_lang tips", "status": "published", "score": int64(30), "tags": []string{"rust", "news"}, "note": "third"},
+	{"id": int64(4), "title": "gardening", "status": "archived", "score": int64(40), "tags": []string{}, "note": nil},
+}
+
+var fixtureTable = NewPostgresTable("posts", "id", "title", "status", "score", "tags", "note")
+
+func leaf(field string, op OperationType, value interface{}) Condition {
+	return Condition{Filter: &Filter{Field: field, Operator: op, Value: value}}
+}
+
+func TestBackendsAgree(t *testing.T) {
+	cases := []struct {
+		name string
+		opts QueryOptions
+		want []int64
+	}{
+		{"equal", QueryOptions{Filters: []Filter{{"status", OpEqual, "published"}}}, []int64{1, 3}},
+		{"not equal", QueryOptions{Filters: []Filter{{"status", OpNotEqual, "published"}}}, []int64{2, 4}},
+		{"greater than", QueryOptions{Filters: []Filter{{"score", OpGreaterThan, int64(20)}}}, []int64{3, 4}},
+		{"less or equal", QueryOptions{Filters: []Filter{{"score", OpLTE, int64(20)}}}, []int64{1, 2}},
+		{"range on one field", QueryOptions{Filters: []Filter{
+			{"score", OpGTE, int64(20)},
+			{"score", OpLessThan, int64(40)},
+		}}, []int64{2, 3}},
+		{"between", QueryOptions{Filters: []Filter{{"score", OpBetween, []int64{20, 30}}}}, []int64{2, 3}},
+		{"in", QueryOptions{Filters: []Filter{{"status", OpIn, []string{"draft", "archived"}}}}, []int64{2, 4}},
+		{"not in", QueryOptions{Filters: []Filter{{"status", OpNotIn, []string{"draft", "archived"}}}}, []int64{1, 3}},
+		{"contains is case-insensitive", QueryOptions{Filters: []Filter{{"title", OpContains, "GO"}}}, []int64{1, 2}},
+		{"contains matches wildcards literally", QueryOptions{Filters: []Filter{{"title", OpContains, "50%"}}}, []int64{2}},
+		{"contains underscore literally", QueryOptions{Filters: []Filter{{"title", OpContains, "t_l"}}}, []int64{3}},
+		{"regex is case-sensitive", QueryOptions{Filters: []Filter{{"title", OpRegex, "^g"}}}, []int64{4}},
+		{"exists", QueryOptions{Filters: []Filter{{"note", OpExists, true}}}, []int64{1, 3}},
+		{"not exists", QueryOptions{Filters: []Filter{{"note", OpExists, false}}}, []int64{2, 4}},
+		{"not equal keeps nulls", QueryOptions{Filters: []Filter{{"note", OpNotEqual, "first"}}}, []int64{2, 3, 4}},
+		{"not in keeps nulls", QueryOptions{Filters: []Filter{{"note", OpNotIn, []string{"first", "third"}}}}, []int64{2, 4}},
+		{"all", QueryOptions{Filters: []Filter{{"tags", OpAll, []string{"go", "news"}}}}, []int64{1}},
+
+		// Repeated filters on one field must all apply, not replace each other.
+		{"two contains", QueryOptions{Filters: []Filter{
+			{"title", OpContains, "go"},
+			{"title", OpContains, "s"},
+		}}, []int64{1, 2}},
+		{"two equals", QueryOptions{Filters: []Filter{
+			{"status", OpEqual, "published"},
+			{"status", OpEqual, "draft"},
+		}}, nil},
+		{"two not in", QueryOptions{Filters: []Filter{
+			{"status", OpNotIn, []string{"draft"}},
+			{"status", OpNotIn, []string{"archived"}},
+		}}, []int64{1, 3}},
+		{"equal then not equal", QueryOptions{Filters: []Filter{
+			{"status", OpEqual, "published"},
+			{"status", OpNotEqual, "published"},
+		}}, nil},
+
+		{"where only", QueryOptions{Where: &Condition{Op: LogicOr, Children: []Condition{
+			leaf("status", OpEqual, "draft"),
+			leaf("score", OpGTE, int64(40)),
+		}}}, []int64{2, 4}},
+		{"filters and where", QueryOptions{
+			Filters: []Filter{{"title", OpContains, "go"}},
+			Where: &Condition{Op: LogicOr, Children: []Condition{
+				leaf("status", OpEqual, "draft"),
+				leaf("score", OpLessThan, int64(15)),
+			}},
+		}, []int64{1, 2}},
+		{"not", QueryOptions{Where: &Condition{Op: LogicNot, Children: []Condition{
+			{Op: LogicAnd, Children: []Condition{
+				leaf("status", OpEqual, "published"),
+				leaf("tags", OpAll, []string{"news"}),
+			}},
+		}}}, []int64{2, 4}},
+		{"filter and where on the same field", QueryOptions{
+			Filters: []Filter{{"status", OpNotIn, []string{"archived"}}},
+			Where:   ptr(leaf("status", OpNotEqual, "draft")),
+		}, []int64{1, 3}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, _ := BuildMongoQuery(tc.opts)
+			var mongoIDs []int64
+			for _, r := range fixtureRows {
+				if matchMongo(t, filter, r) {
+					mongoIDs = append(mongoIDs, r["id"].(int64))
+				}
+			}
+
+			where, args, err := postgresWhere(fixtureTable, tc.opts, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sqlIDs []int64
+			for _, r := range fixtureRows {
+				if matchSQL(t, strings.TrimPrefix(where, " WHERE "), args, r) {
+					sqlIDs = append(sqlIDs, r["id"].(int64))
+				}
+			}
+
+			if !reflect.DeepEqual(mongoIDs, tc.want) {
+				t.Errorf("mongo %v matched %v, want %v", filter, mongoIDs, tc.want)
+			}
+			if !reflect.DeepEqual(sqlIDs, tc.want) {
+				t.Errorf("postgres %q %v matched %v, want %v", where, args, sqlIDs, tc.want)
+			}
+		})
+	}
+}
+
+func ptr(c Condition) *Condition { return &c }
+
+// matchMongo evaluates the subset of MongoDB query operators the builder emits.
+func matchMongo(t *testing.T, filter bson.M, r row) bool {
+	t.Helper()
+	for key, value := range filter {
+		switch key {
+		case "$and", "$or", "$nor":
+			any, all := false, true
+			for _, child := range value.(bson.A) {
+				if matchMongo(t, child.(bson.M), r) {
+					any = true
+				} else {
+					all = false
+				}
+			}
+			if (key == "$and" && !all) || (key == "$or" && !any) || (key == "$nor" && any) {
+				return false
+			}
+		default:
+			ops, ok := value.(bson.M)
+			if !ok {
+				ops = bson.M{"$eq": value}
+			}
+			if !matchMongoField(t, ops, r[key]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchMongoField(t *testing.T, ops bson.M, field interface{}) bool {
+	t.Helper()
+	// Operators on an array field match when any element does, except $all.
+	elems := []interface{}{field}
+	if v := reflect.ValueOf(field); field != nil && v.Kind() == reflect.Slice {
+		elems = elems[:0]
+		for i := 0; i < v.Len(); i++ {
+			elems = append(elems, v.Index(i).Interface())
+		}
+	}
+	anyElem := func(pred func(interface{}) bool) bool {
+		for _, e := range elems {
+			if pred(e) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for op, want := range ops {
+		var ok bool
+		switch op {
+		case "$eq":
+			ok = anyElem(func(e interface{}) bool { return compare(e, want) == 0 })
+		case "$ne":
+			ok = !anyElem(func(e interface{}) bool { return compare(e, want) == 0 })
+		case "$gt":
+			ok = anyElem(func(e interface{}) bool { return e != nil && compare(e, want) > 0 })
+		case "$gte":
+			ok = anyElem(func(e interface{}) bool { return e != nil && compare(e, want) >= 0 })
+		case "$lt":
+			ok = anyElem(func(e interface{}) bool { return e != nil && compare(e, want) < 0 })
+		case "$lte":
+			ok = anyElem(func(e interface{}) bool { return e != nil && compare(e, want) <= 0 })
+		case "$in", "$nin":
+			in := anyElem(func(e interface{}) bool { return contains(want, e) })
+			ok = in == (op == "$in")
+		case "$all":
+			ok = true
+			list := reflect.ValueOf(want)
+			for i := 0; i < list.Len(); i++ {
+				if !contains(field, list.Index(i).Interface()) {
+					ok = false
+				}
+			}
+		case "$regex":
+			pattern := want.(string)
+			if ops["$options"] == "i" {
+				pattern = "(?i)" + pattern
+			}
+			re := regexp.MustCompile(pattern)
+			ok = anyElem(func(e interface{}) bool { s, isStr := e.(string); return isStr && re.MatchString(s) })
+		case "$options":
+			ok = true
+		default:
+			t.Fatalf("matchMongo: unsupported operator %s", op)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSQL evaluates the WHERE clauses postgresWhere renders, with NULL
+// comparisons treated as false like SQL does in a WHERE clause.
+func matchSQL(t *testing.T, where string, args []interface{}, r row) bool {
+	t.Helper()
+	if where == "" {
+		return true
+	}
+	p := &sqlEval{t: t, tokens: tokenizeSQL(t, where), args: args, row: r}
+	result := p.or()
+	if p.pos != len(p.tokens) {
+		t.Fatalf("matchSQL: unexpected %q in %q", p.tokens[p.pos], where)
+	}
+	return result
+}
+
+type sqlEval struct {
+	t      *testing.T
+	tokens []string
+	pos    int
+	args   []interface{}
+	row    row
+}
+
+func (p *sqlEval) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *sqlEval) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *sqlEval) expect(tok string) {
+	if got := p.next(); got != tok {
+		p.t.Fatalf("matchSQL: expected %q, got %q", tok, got)
+	}
+}
+
+func (p *sqlEval) or() bool {
+	result := p.and()
+	for p.peek() == "OR" {
+		p.next()
+		right := p.and()
+		result = result || right
+	}
+	return result
+}
+
+func (p *sqlEval) and() bool {
+	result := p.factor()
+	for p.peek() == "AND" {
+		p.next()
+		right := p.factor()
+		result = result && right
+	}
+	return result
+}
+
+func (p *sqlEval) factor() bool {
+	switch p.peek() {
+	case "NOT":
+		p.next()
+		return !p.factor()
+	case "(":
+		p.next()
+		result := p.or()
+		p.expect(")")
+		return result
+	}
+	return p.predicate()
+}
+
+func (p *sqlEval) arg() interface{} {
+	tok := p.next()
+	n, err := strconv.Atoi(strings.TrimPrefix(tok, "$"))
+	if err != nil || !strings.HasPrefix(tok, "$") {
+		p.t.Fatalf("matchSQL: expected placeholder, got %q", tok)
+	}
+	switch v := p.args[n-1].(type) {
+	case *pq.StringArray:
+		return []string(*v)
+	case *pq.Int64Array:
+		return []int64(*v)
+	default:
+		return v
+	}
+}
+
+func (p *sqlEval) predicate() bool {
+	ident := p.next()
+	if !strings.HasPrefix(ident, `"`) {
+		p.t.Fatalf("matchSQL: expected column, got %q", ident)
+	}
+	value := p.row[strings.Trim(ident, `"`)]
+
+	switch op := p.next(); op {
+	case "IS":
+		not := p.peek() == "NOT"
+		if not {
+			p.next()
+		}
+		p.expect("NULL")
+		return (value == nil) != not
+	case "BETWEEN":
+		lo := p.arg()
+		p.expect("AND")
+		hi := p.arg()
+		return value != nil && compare(value, lo) >= 0 && compare(value, hi) <= 0
+	case "=":
+		if p.peek() == "ANY" {
+			p.next()
+			p.expect("(")
+			list := p.arg()
+			p.expect(")")
+			return value != nil && contains(list, value)
+		}
+		want := p.arg()
+		return value != nil && compare(value, want) == 0
+	case "!=", ">", ">=", "<", "<=":
+		want := p.arg()
+		if value == nil {
+			return false
+		}
+		c := compare(value, want)
+		return map[string]bool{"!=": c != 0, ">": c > 0, ">=": c >= 0, "<": c < 0, "<=": c <= 0}[op]
+	case "ILIKE":
+		pattern := p.arg().(string)
+		s, ok := value.(string)
+		return ok && likePattern(pattern).MatchString(s)
+	case "~":
+		pattern := p.arg().(string)
+		s, ok := value.(string)
+		return ok && regexp.MustCompile(pattern).MatchString(s)
+	case "@>":
+		list := reflect.ValueOf(p.arg())
+		for i := 0; i < list.Len(); i++ {
+			if !contains(value, list.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	default:
+		p.t.Fatalf("matchSQL: unsupported operator %q", op)
+		return false
+	}
+}
+
+// likePattern turns an ILIKE pattern with backslash escapes into a regexp.
+func likePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '\\':
+			i++
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func tokenizeSQL(t *testing.T, s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+			j := strings.IndexByte(s[i+1:], '"')
+			tokens = append(tokens, s[i:i+j+2])
+			i += j + 2
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '$' || unicode.IsLetter(c):
+			j := i + 1
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j]))) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		case strings.ContainsRune("=!<>~@", c):
+			j := i + 1
+			for j < len(s) && strings.ContainsRune("=<>", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			t.Fatalf("tokenizeSQL: unexpected %q in %q", c, s)
+		}
+	}
+	return tokens
+}
+
+// compare orders numbers numerically and everything else by its string form.
+func compare(a, b interface{}) int {
+	if a == nil || b == nil {
+		if a == b {
+			return 0
+		}
+		return -1
+	}
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+	if aNum && bNum {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// contains reports whether the slice list holds v.
+func contains(list, v interface{}) bool {
+	l := reflect.ValueOf(list)
+	if list == nil || l.Kind() != reflect.Slice {
+		return false
+	}
+	for i := 0; i < l.Len(); i++ {
+		if compare(l.Index(i).Interface(), v) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBuildMongoQueryKeepsRepeatedFilters(t *testing.T) {
+	filter, _ := BuildMongoQuery(QueryOptions{Filters: []Filter{
+		{"categories", OpNotIn, []string{"a"}},
+		{"status", OpEqual, "published"},
+		{"categories", OpNotIn, []string{"b"}},
+	}})
+	want := bson.M{"$and": bson.A{
+		bson.M{"categories": bson.M{"$nin": []string{"a"}}, "status": "published"},
+		bson.M{"categories": bson.M{"$nin": []string{"b"}}},
+	}}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("filter = %v, want %v", filter, want)
+	}
+
+	filter, _ = BuildMongoQuery(QueryOptions{Filters: []Filter{{"status", OpEqual, "published"}}})
+	if !reflect.DeepEqual(filter, bson.M{"status": "published"}) {
+		t.Errorf("single filter = %v, want a plain equality", filter)
+	}
+}
+
+func TestPostgresNegationsKeepNulls(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{Filter{"note", OpNotEqual, "first"}, ` WHERE ("note" IS NULL OR "note" != $1)`},
+		{Filter{"note", OpNotIn, []string{"first"}}, ` WHERE ("note" IS NULL OR NOT ("note" = ANY($1)))`},
+	}
+	for _, tc := range tests {
+		where, _, err := postgresWhere(fixtureTable, QueryOptions{Filters: []Filter{tc.filter}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if where != tc.want {
+			t.Errorf("%s: where = %q, want %q", tc.filter.Operator, where, tc.want)
+		}
+	}
+}
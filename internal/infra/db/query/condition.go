@@ -0,0 +1,148 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LogicOp combines the children of a Condition group.
+type LogicOp string
+
+const (
+	LogicAnd LogicOp = "and"
+	LogicOr  LogicOp = "or"
+	LogicNot LogicOp = "not" // exactly one child
+)
+
+// Limits on client-supplied expressions, so one request cannot make the
+// database evaluate an arbitrarily large predicate.
+const (
+	MaxConditionDepth = 4
+	MaxConditionNodes = 32
+)
+
+// Condition is a node of a boolean filter expression: a leaf holding one
+// Filter, or a group applying Op to its Children.
+type Condition struct {
+	Filter   *Filter
+	Op       LogicOp
+	Children []Condition
+}
+
+// Leaves returns the filters at the leaves of the expression, so callers can
+// rewrite them in place.
+func (c *Condition) Leaves() []*Filter {
+	if c == nil {
+		return nil
+	}
+	if c.Filter != nil {
+		return []*Filter{c.Filter}
+	}
+	var leaves []*Filter
+	for i := range c.Children {
+		leaves = append(leaves, c.Children[i].Leaves()...)
+	}
+	return leaves
+}
+
+// ParseCondition parses the "where" query-string syntax: a raw filter as
+// accepted by ParseFilters, or a group of expressions separated by ";":
+//
+//	or(status__==__draft;and(author_id__==__5;not(title__contains__wip)))
+//
+// A backslash makes the next character literal, for values that contain
+// "(", ")", ";" or "\". An empty string yields a nil Condition.
+func ParseCondition(raw string) (*Condition, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	p := &conditionParser{input: []rune(raw)}
+	cond, err := p.parse(1)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.input) {
+		return nil, fmt.Errorf("invalid where expression: unexpected %q at position %d", string(p.input[p.pos]), p.pos)
+	}
+	return cond, nil
+}
+
+type conditionParser struct {
+	input []rune
+	pos   int
+	nodes int
+}
+
+func (p *conditionParser) parse(depth int) (*Condition, error) {
+	p.nodes++
+	if p.nodes > MaxConditionNodes {
+		return nil, fmt.Errorf("invalid where expression: more than %d terms", MaxConditionNodes)
+	}
+
+	for _, op := range []LogicOp{LogicAnd, LogicOr, LogicNot} {
+		prefix := string(op) + "("
+		if !strings.HasPrefix(string(p.input[p.pos:]), prefix) {
+			continue
+		}
+		if depth > MaxConditionDepth {
+			return nil, fmt.Errorf("invalid where expression: groups nested deeper than %d", MaxConditionDepth)
+		}
+		p.pos += len(prefix)
+
+		group := &Condition{Op: op}
+		for {
+			child, err := p.parse(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			group.Children = append(group.Children, *child)
+
+			if p.pos >= len(p.input) {
+				return nil, fmt.Errorf("invalid where expression: missing ) for %s(", op)
+			}
+			if p.input[p.pos] == ')' {
+				p.pos++
+				break
+			}
+			p.pos++ // ';'
+		}
+		if op == LogicNot && len(group.Children) != 1 {
+			return nil, fmt.Errorf("invalid where expression: not() takes exactly one term")
+		}
+		return group, nil
+	}
+
+	raw, err := p.readFilter()
+	if err != nil {
+		return nil, err
+	}
+	filters, err := ParseFilters([]string{raw})
+	if err != nil {
+		return nil, err
+	}
+	return &Condition{Filter: &filters[0]}, nil
+}
+
+// readFilter consumes a raw filter up to the next unescaped ";" or ")".
+func (p *conditionParser) readFilter() (string, error) {
+	var b strings.Builder
+	for p.pos < len(p.input) {
+		r := p.input[p.pos]
+		switch r {
+		case '\\':
+			if p.pos+1 >= len(p.input) {
+				return "", fmt.Errorf("invalid where expression: trailing backslash")
+			}
+			b.WriteRune(p.input[p.pos+1])
+			p.pos += 2
+			continue
+		case ';', ')':
+			return b.String(), nil
+		case '(':
+			return "", fmt.Errorf("invalid where expression: unexpected ( at position %d", p.pos)
+		}
+		b.WriteRune(r)
+		p.pos++
+	}
+	return b.String(), nil
+}
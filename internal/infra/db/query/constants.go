@@ -9,4 +9,14 @@ const (
 	OpLessThan    OperationType = "<"
 	OpGTE         OperationType = ">="
 	OpLTE         OperationType = "<="
+	OpIn          OperationType = "in"       // value is a slice
+	OpNotIn       OperationType = "nin"      // value is a slice
+	OpAll         OperationType = "all"      // value is a slice; the array field must hold every element
+	OpBetween     OperationType = "between"  // value is a two-element slice, bounds inclusive
+	OpContains    OperationType = "contains" // value is a literal substring, matched case-insensitively
+	OpRegex       OperationType = "regex"    // value is a regular expression, matched case-sensitively
+	OpExists      OperationType = "exists"   // value is a bool; true means present and not null
 )
+
+// MaxRegexLength caps user-supplied patterns for OpRegex.
+const MaxRegexLength = 100
@@ -0,0 +1,227 @@
+package query
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cursor is a decoded keyset position: the sort-key values of the row at the
+// edge of a page, and whether to read the rows before it instead of after.
+type Cursor struct {
+	Values []interface{}
+	Before bool
+}
+
+// PageInfo describes where a page sits in its listing. The cursors are empty
+// when there is nothing in that direction; Total is nil when the caller opted
+// out of counting.
+type PageInfo struct {
+	NextCursor string
+	PrevCursor string
+	HasNext    bool
+	HasPrev    bool
+	Total      *int64
+}
+
+// TotalPages is the number of pages of the given size, or 0 when the total is unknown.
+func (p PageInfo) TotalPages(pageSize int) int64 {
+	if p.Total == nil || pageSize <= 0 {
+		return 0
+	}
+	return (*p.Total + int64(pageSize) - 1) / int64(pageSize)
+}
+
+type cursorPayload struct {
+	Fields []string `bson:"f"`
+	Values bson.A   `bson:"v"`
+	Times  []int    `bson:"t,omitempty"` // indexes of values stored as Unix nanoseconds
+	Before bool     `bson:"b,omitempty"`
+}
+
+// EncodeCursor builds an opaque token for the row with the given sort-key
+// values. The sort fields are recorded so a token cannot be replayed against
+// a different ordering. BSON keeps ObjectIDs and Mongo dates typed across the
+// trip; Go times, which Postgres returns with microseconds, are stored as
+// nanoseconds since BSON dates would truncate them to milliseconds.
+func EncodeCursor(sorts []Sort, values []interface{}, before bool) (string, error) {
+	payload := cursorPayload{Fields: sortFields(sorts), Values: make(bson.A, len(values)), Before: before}
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok {
+			v = t.UnixNano()
+			payload.Times = append(payload.Times, i)
+		}
+		payload.Values[i] = v
+	}
+	raw, err := bson.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor reads a token produced by EncodeCursor for the same sorts.
+func DecodeCursor(token string, sorts []Sort) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var payload cursorPayload
+	if err := bson.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	fields := sortFields(sorts)
+	if len(payload.Fields) != len(fields) || len(payload.Values) != len(fields) {
+		return nil, fmt.Errorf("cursor does not match the requested sort")
+	}
+	for i := range fields {
+		if payload.Fields[i] != fields[i] {
+			return nil, fmt.Errorf("cursor does not match the requested sort")
+		}
+	}
+
+	values := make([]interface{}, len(payload.Values))
+	for i, v := range payload.Values {
+		// Hand back time.Time so SQL drivers can bind it too.
+		if dt, ok := v.(primitive.DateTime); ok {
+			v = dt.Time()
+		}
+		values[i] = v
+	}
+	for _, i := range payload.Times {
+		if i < 0 || i >= len(values) {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		nanos, ok := values[i].(int64)
+		if !ok {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		values[i] = time.Unix(0, nanos).UTC()
+	}
+	return &Cursor{Values: values, Before: payload.Before}, nil
+}
+
+// NullOrder is where a backend sorts null (or, in MongoDB, missing) sort keys.
+type NullOrder int
+
+const (
+	// NullsSmallest sorts nulls before every value ascending and after every
+	// value descending, as MongoDB does.
+	NullsSmallest NullOrder = iota
+	// NullsLargest is the opposite, PostgreSQL's default of NULLS LAST
+	// ascending and NULLS FIRST descending.
+	NullsLargest
+)
+
+// KeysetCondition selects the rows strictly after the cursor in sort order,
+// or strictly before it when the cursor points backwards. For sorts a, b it
+// reads a > x OR (a = x AND b > y), flipping each comparison for descending fields.
+//
+// Comparisons with null match nothing in either backend, so null keys are
+// handled explicitly according to nulls: a null in the cursor becomes an
+// IS NULL / IS NOT NULL test, and rows whose key is null are added to a
+// comparison when they sort past a non-null cursor value. The last sort key
+// must be a unique, non-null tie-breaker such as the primary key.
+func KeysetCondition(sorts []Sort, cursor *Cursor, nulls NullOrder) *Condition {
+	or := &Condition{Op: LogicOr}
+	for i, s := range sorts {
+		and := Condition{Op: LogicAnd}
+		for j := 0; j < i; j++ {
+			and.Children = append(and.Children, equalOrNull(sorts[j].Field, cursor.Values[j]))
+		}
+
+		// Whether the rows wanted have larger keys than the cursor's.
+		greater := s.Desc == cursor.Before
+		value := cursor.Values[i]
+		switch {
+		case value == nil && greater == (nulls == NullsLargest):
+			// Nothing sorts past null in this direction.
+			continue
+		case value == nil:
+			f := Filter{Field: s.Field, Operator: OpExists, Value: true}
+			and.Children = append(and.Children, Condition{Filter: &f})
+		default:
+			op := OpGreaterThan
+			if !greater {
+				op = OpLessThan
+			}
+			f := Filter{Field: s.Field, Operator: op, Value: value}
+			cond := Condition{Filter: &f}
+			if greater == (nulls == NullsLargest) {
+				cond = Condition{Op: LogicOr, Children: []Condition{cond, equalOrNull(s.Field, nil)}}
+			}
+			and.Children = append(and.Children, cond)
+		}
+		or.Children = append(or.Children, and)
+	}
+	return or
+}
+
+// equalOrNull matches rows whose field equals value, or is null when value is nil.
+func equalOrNull(field string, value interface{}) Condition {
+	f := Filter{Field: field, Operator: OpEqual, Value: value}
+	if value == nil {
+		f = Filter{Field: field, Operator: OpExists, Value: false}
+	}
+	return Condition{Filter: &f}
+}
+
+// ReverseSorts flips every sort direction, for reading a page backwards.
+func ReverseSorts(sorts []Sort) []Sort {
+	reversed := make([]Sort, len(sorts))
+	for i, s := range sorts {
+		reversed[i] = Sort{Field: s.Field, Desc: !s.Desc}
+	}
+	return reversed
+}
+
+// And combines conditions, skipping nil ones.
+func And(conds ...*Condition) *Condition {
+	group := &Condition{Op: LogicAnd}
+	for _, c := range conds {
+		if c != nil {
+			group.Children = append(group.Children, *c)
+		}
+	}
+	switch len(group.Children) {
+	case 0:
+		return nil
+	case 1:
+		return &group.Children[0]
+	default:
+		return group
+	}
+}
+
+// WithSortFields extends a projection with any sort field it lacks, so cursors
+// can be built from the returned rows. An empty projection already has them all.
+func WithSortFields(fields []string, sorts []Sort) []string {
+	if len(fields) == 0 {
+		return fields
+	}
+	for _, s := range sorts {
+		found := false
+		for _, f := range fields {
+			if f == s.Field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fields = append(fields, s.Field)
+		}
+	}
+	return fields
+}
+
+func sortFields(sorts []Sort) []string {
+	fields := make([]string, len(sorts))
+	for i, s := range sorts {
+		fields[i] = s.Field
+	}
+	return fields
+}
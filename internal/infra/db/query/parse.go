@@ -2,10 +2,14 @@ package query
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
 // ParseFilters converts raw filter strings (e.g. "age__gt__30") into Filter objects.
+// List operators take comma-separated values, e.g. "categories__in__a,b" or
+// "created_at__between__2025-01-01,2025-02-01".
 func ParseFilters(rawFilters []string) ([]Filter, error) {
 	var filters []Filter
 	for _, f := range rawFilters {
@@ -30,14 +34,52 @@ func ParseFilters(rawFilters []string) ([]Filter, error) {
 			op = OpGTE
 		case "<=":
 			op = OpLTE
+		case "in", "nin", "all", "between", "contains", "regex", "exists":
+			op = OperationType(opString)
 		default:
 			return nil, fmt.Errorf("unsupported operator: %s", opString)
 		}
-		filters = append(filters, Filter{Field: field, Operator: op, Value: val})
+
+		value, err := parseFilterValue(op, val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %s: %w", f, err)
+		}
+		filters = append(filters, Filter{Field: field, Operator: op, Value: value})
 	}
 	return filters, nil
 }
 
+func parseFilterValue(op OperationType, val string) (interface{}, error) {
+	switch op {
+	case OpIn, OpNotIn, OpAll:
+		return strings.Split(val, ","), nil
+	case OpBetween:
+		bounds := strings.Split(val, ",")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("between needs exactly two comma-separated bounds")
+		}
+		return bounds, nil
+	case OpExists:
+		exists, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("exists needs true or false")
+		}
+		return exists, nil
+	case OpRegex:
+		// Only RE2 syntax is accepted, which rules out backreferences and
+		// lookarounds; the length cap bounds what is left for the database.
+		if len(val) > MaxRegexLength {
+			return nil, fmt.Errorf("regex longer than %d characters", MaxRegexLength)
+		}
+		if _, err := regexp.Compile(val); err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		return val, nil
+	default:
+		return val, nil
+	}
+}
+
 // ParseSorts converts raw sort strings (e.g. "age__desc") into Sort objects.
 func ParseSorts(rawSorts []string) ([]Sort, error) {
 	var sorts []Sort
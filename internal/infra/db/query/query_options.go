@@ -16,6 +16,7 @@ type Sort struct {
 // QueryOptions is a container for all query customizations.
 type QueryOptions struct {
 	Filters []Filter
+	Where   *Condition // ANDed with Filters
 	Sorts   []Sort
 	Fields  []string
 	Skip    int64
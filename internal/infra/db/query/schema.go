@@ -0,0 +1,275 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldType is the type a filter value is coerced to before it reaches the database.
+type FieldType string
+
+const (
+	FieldString   FieldType = "string"
+	FieldInt      FieldType = "int"
+	FieldBool     FieldType = "bool"
+	FieldTime     FieldType = "time" // RFC 3339, or a bare 2006-01-02 date
+	FieldObjectID FieldType = "objectid"
+	FieldEnum     FieldType = "enum" // one of FieldSpec.Values; only ==, !=, in and nin apply
+)
+
+// enumOperators are the only operators meaningful on an enum field.
+var enumOperators = map[OperationType]bool{OpEqual: true, OpNotEqual: true, OpIn: true, OpNotIn: true}
+
+// FieldSpec describes one field clients may name in filter, sort or fields
+// parameters. Array fields use the type of their elements.
+type FieldSpec struct {
+	Name       string    // name used in the query string
+	Column     string    // name in storage; defaults to Name
+	Type       FieldType // how filter values are coerced
+	Values     []string  // the allowed values of a FieldEnum
+	Filterable bool
+	Sortable   bool
+}
+
+// Schema is the set of fields an entity exposes to the query DSL.
+type Schema struct {
+	fields map[string]FieldSpec
+}
+
+func NewSchema(fields ...FieldSpec) *Schema {
+	s := &Schema{fields: make(map[string]FieldSpec, len(fields))}
+	for _, f := range fields {
+		if f.Column == "" {
+			f.Column = f.Name
+		}
+		s.fields[f.Name] = f
+	}
+	return s
+}
+
+// FieldError reports a filter, sort or projection the schema does not allow.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %q: %s", e.Field, e.Reason)
+}
+
+// Filters checks parsed filters against the schema, renames them to their
+// storage columns and coerces their values to the field's type.
+func (s *Schema) Filters(filters []Filter) ([]Filter, error) {
+	out := make([]Filter, 0, len(filters))
+	for _, f := range filters {
+		spec, ok := s.fields[f.Field]
+		if !ok {
+			return nil, &FieldError{Field: f.Field, Reason: "unknown field"}
+		}
+		if !spec.Filterable {
+			return nil, &FieldError{Field: f.Field, Reason: "not filterable"}
+		}
+		if (f.Operator == OpContains || f.Operator == OpRegex) && spec.Type != FieldString {
+			return nil, &FieldError{Field: f.Field, Reason: fmt.Sprintf("%s only applies to text fields", f.Operator)}
+		}
+
+		if spec.Type == FieldEnum {
+			if err := spec.checkEnum(f); err != nil {
+				return nil, err
+			}
+		}
+
+		value, err := coerce(spec.Type, f.Value)
+		if err != nil {
+			return nil, &FieldError{Field: f.Field, Reason: err.Error()}
+		}
+		out = append(out, Filter{Field: spec.Column, Operator: f.Operator, Value: value})
+	}
+	return out, nil
+}
+
+// checkEnum rejects operators other than ==, !=, in and nin on an enum field,
+// and values outside its allowed set.
+func (spec FieldSpec) checkEnum(f Filter) error {
+	if !enumOperators[f.Operator] {
+		return &FieldError{Field: f.Field, Reason: fmt.Sprintf("%s does not apply to an enum field; use ==, !=, in or nin", f.Operator)}
+	}
+	values, ok := f.Value.([]string)
+	if !ok {
+		values = []string{fmt.Sprint(f.Value)}
+	}
+	for _, v := range values {
+		if !slices.Contains(spec.Values, v) {
+			return &FieldError{Field: f.Field, Reason: fmt.Sprintf("expects one of %s, got %q", strings.Join(spec.Values, ", "), v)}
+		}
+	}
+	return nil
+}
+
+// EnumValues lists the values of a string-backed enum for FieldSpec.Values.
+func EnumValues[T ~string](values []T) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = string(v)
+	}
+	return out
+}
+
+// Condition checks every leaf of a boolean expression like Filters does,
+// returning a rewritten copy.
+func (s *Schema) Condition(cond *Condition) (*Condition, error) {
+	if cond == nil {
+		return nil, nil
+	}
+	if cond.Filter != nil {
+		filters, err := s.Filters([]Filter{*cond.Filter})
+		if err != nil {
+			return nil, err
+		}
+		return &Condition{Filter: &filters[0]}, nil
+	}
+
+	out := &Condition{Op: cond.Op, Children: make([]Condition, 0, len(cond.Children))}
+	for i := range cond.Children {
+		child, err := s.Condition(&cond.Children[i])
+		if err != nil {
+			return nil, err
+		}
+		out.Children = append(out.Children, *child)
+	}
+	return out, nil
+}
+
+// Sorts checks parsed sorts against the schema and renames them to their storage columns.
+func (s *Schema) Sorts(sorts []Sort) ([]Sort, error) {
+	out := make([]Sort, 0, len(sorts))
+	for _, srt := range sorts {
+		spec, ok := s.fields[srt.Field]
+		if !ok {
+			return nil, &FieldError{Field: srt.Field, Reason: "unknown field"}
+		}
+		if !spec.Sortable {
+			return nil, &FieldError{Field: srt.Field, Reason: "not sortable"}
+		}
+		out = append(out, Sort{Field: spec.Column, Desc: srt.Desc})
+	}
+	return out, nil
+}
+
+// Fields checks a projection against the schema and renames it to storage columns.
+func (s *Schema) Fields(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	out := make([]string, 0, len(fields))
+	for _, name := range fields {
+		spec, ok := s.fields[name]
+		if !ok {
+			return nil, &FieldError{Field: name, Reason: "unknown field"}
+		}
+		out = append(out, spec.Column)
+	}
+	return out, nil
+}
+
+// coerce converts a value produced by ParseFilters: a string, a list of
+// strings for list operators, or exists' bool, which is left as is.
+func coerce(t FieldType, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return coerceOne(t, v)
+	case []string:
+		return coerceList(t, v)
+	default:
+		// Already typed by the caller.
+		return v, nil
+	}
+}
+
+func coerceList(t FieldType, values []string) (interface{}, error) {
+	switch t {
+	case FieldInt:
+		out := make([]int64, len(values))
+		for i, v := range values {
+			n, err := coerceOne(t, v)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = n.(int64)
+		}
+		return out, nil
+	case FieldBool:
+		out := make([]bool, len(values))
+		for i, v := range values {
+			b, err := coerceOne(t, v)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = b.(bool)
+		}
+		return out, nil
+	case FieldTime:
+		out := make([]time.Time, len(values))
+		for i, v := range values {
+			ts, err := coerceOne(t, v)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = ts.(time.Time)
+		}
+		return out, nil
+	case FieldObjectID:
+		out := make([]primitive.ObjectID, len(values))
+		for i, v := range values {
+			oid, err := coerceOne(t, v)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = oid.(primitive.ObjectID)
+		}
+		return out, nil
+	default:
+		return values, nil
+	}
+}
+
+func coerceOne(t FieldType, value string) (interface{}, error) {
+	switch t {
+	case FieldInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expects an integer, got %q", value)
+		}
+		return n, nil
+	case FieldBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expects true or false, got %q", value)
+		}
+		return b, nil
+	case FieldTime:
+		if ts, err := time.Parse(time.RFC3339, value); err == nil {
+			return ts, nil
+		}
+		ts, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, fmt.Errorf("expects an RFC 3339 time or YYYY-MM-DD date, got %q", value)
+		}
+		return ts, nil
+	case FieldObjectID:
+		oid, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, fmt.Errorf("expects an ObjectID, got %q", value)
+		}
+		return oid, nil
+	default:
+		return value, nil
+	}
+}
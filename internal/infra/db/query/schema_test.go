@@ -0,0 +1,51 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaEnumFields(t *testing.T) {
+	schema := NewSchema(FieldSpec{Name: "status", Type: FieldEnum, Values: []string{"active", "banned"}, Filterable: true})
+
+	tests := []struct {
+		raw     string
+		want    interface{}
+		wantErr bool
+	}{
+		{"status__==__active", "active", false},
+		{"status__!=__banned", "banned", false},
+		{"status__in__active,banned", []string{"active", "banned"}, false},
+		{"status__nin__banned", []string{"banned"}, false},
+		{"status__==__Active", nil, true},
+		{"status__in__active,gone", nil, true},
+		{"status__contains__act", nil, true},
+		{"status__regex__^a", nil, true},
+		{"status__>__active", nil, true},
+		{"status__between__active,banned", nil, true},
+		{"status__exists__true", nil, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.raw, func(t *testing.T) {
+			parsed, err := ParseFilters([]string{tc.raw})
+			if err != nil {
+				t.Fatal(err)
+			}
+			filters, err := schema.Filters(parsed)
+			if tc.wantErr {
+				var fieldErr *FieldError
+				if !errors.As(err, &fieldErr) || fieldErr.Field != "status" {
+					t.Fatalf("got %v, want a FieldError for status", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(filters[0].Value, tc.want) {
+				t.Errorf("value = %#v, want %#v", filters[0].Value, tc.want)
+			}
+		})
+	}
+}
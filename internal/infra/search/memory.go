@@ -0,0 +1,106 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryPostSearcher is an in-process PostSearcher for tests and local runs
+// without MongoDB. Posts are added with Index; scoring sums the field weight
+// of every query term found in a field, once per occurrence.
+type MemoryPostSearcher struct {
+	mu    sync.RWMutex
+	posts map[primitive.ObjectID]entity.BlogPost
+}
+
+func NewMemoryPostSearcher() *MemoryPostSearcher {
+	return &MemoryPostSearcher{posts: make(map[primitive.ObjectID]entity.BlogPost)}
+}
+
+// Index adds posts, replacing any already indexed under the same ID.
+func (m *MemoryPostSearcher) Index(posts ...entity.BlogPost) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, post := range posts {
+		m.posts[post.ID] = post
+	}
+}
+
+// Remove drops a post from the index.
+func (m *MemoryPostSearcher) Remove(id primitive.ObjectID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.posts, id)
+}
+
+func (m *MemoryPostSearcher) SearchPosts(ctx context.Context, q Query) (Result, error) {
+	terms := Terms(q.Text)
+	if len(terms) == 0 {
+		return Result{Hits: []Hit{}}, nil
+	}
+
+	m.mu.RLock()
+	var hits []Hit
+	for _, post := range m.posts {
+		if excluded(post, q) {
+			continue
+		}
+		if score := score(post, terms); score > 0 {
+			hits = append(hits, Hit{Post: post, Score: score})
+		}
+	}
+	m.mu.RUnlock()
+
+	// Best first, then newest first like the MongoDB engine.
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Post.ID.Hex() > hits[j].Post.ID.Hex()
+	})
+
+	result := Result{Hits: []Hit{}, Total: int64(len(hits))}
+	if q.Skip >= int64(len(hits)) {
+		return result, nil
+	}
+	end := int64(len(hits))
+	if q.Limit > 0 && q.Skip+q.Limit < end {
+		end = q.Skip + q.Limit
+	}
+	result.Hits = hits[q.Skip:end]
+	return result, nil
+}
+
+func excluded(post entity.BlogPost, q Query) bool {
+	if len(q.Statuses) > 0 && !constant.IsValid(post.Status, q.Statuses) {
+		return true
+	}
+	for _, id := range post.Categories {
+		if constant.IsValid(id, q.ExcludeCategories) {
+			return true
+		}
+	}
+	return false
+}
+
+func score(post entity.BlogPost, terms []string) float64 {
+	var total float64
+	for field, texts := range fieldTexts(post) {
+		for _, text := range texts {
+			for _, word := range words(text) {
+				for _, term := range terms {
+					if strings.HasPrefix(word, term) {
+						total += float64(FieldWeights[field])
+					}
+				}
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,135 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newPost(title, heading, paragraph string) entity.BlogPost {
+	post := entity.BlogPost{
+		ID:     primitive.NewObjectID(),
+		Title:  title,
+		Status: constant.BlogStatusPublished,
+	}
+	if heading != "" {
+		post.Blocks = append(post.Blocks, entity.Block{
+			Type:    entity.BlockTypeHeading,
+			Heading: &entity.HeadingBlock{Level: 2, Text: heading},
+		})
+	}
+	if paragraph != "" {
+		post.Blocks = append(post.Blocks, entity.Block{
+			Type: entity.BlockTypeText,
+			Text: &entity.TextBlock{Paragraphs: []entity.Paragraph{{Text: paragraph}}},
+		})
+	}
+	return post
+}
+
+func TestMemoryPostSearcherRanksByFieldWeight(t *testing.T) {
+	inParagraph := newPost("Weekly notes", "", "We finally tried golang generics.")
+	inHeading := newPost("Weekly notes", "Golang tips", "")
+	inTitle := newPost("Golang in production", "", "")
+	unrelated := newPost("Gardening", "", "Tomatoes and basil.")
+
+	searcher := NewMemoryPostSearcher()
+	searcher.Index(inParagraph, inHeading, inTitle, unrelated)
+
+	result, err := searcher.SearchPosts(context.Background(), Query{Text: "golang"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 3 {
+		t.Fatalf("Total = %d, want 3", result.Total)
+	}
+	want := []primitive.ObjectID{inTitle.ID, inHeading.ID, inParagraph.ID}
+	for i, hit := range result.Hits {
+		if hit.Post.ID != want[i] {
+			t.Errorf("hit %d = %q, want %q", i, hit.Post.Title, []string{"title", "heading", "paragraph"}[i])
+		}
+	}
+	if result.Hits[0].Score <= result.Hits[1].Score || result.Hits[1].Score <= result.Hits[2].Score {
+		t.Errorf("scores not strictly decreasing: %v, %v, %v", result.Hits[0].Score, result.Hits[1].Score, result.Hits[2].Score)
+	}
+}
+
+func TestMemoryPostSearcherMatchesWordPrefixes(t *testing.T) {
+	post := newPost("Running a marathon", "", "")
+	searcher := NewMemoryPostSearcher()
+	searcher.Index(post)
+
+	result, err := searcher.SearchPosts(context.Background(), Query{Text: "RUN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 {
+		t.Fatalf("Total = %d, want 1", result.Total)
+	}
+}
+
+func TestMemoryPostSearcherFilters(t *testing.T) {
+	restricted := primitive.NewObjectID()
+
+	published := newPost("Go release notes", "", "")
+	draft := newPost("Go draft", "", "")
+	draft.Status = constant.BlogStatusDraft
+	private := newPost("Go members only", "", "")
+	private.Categories = []primitive.ObjectID{restricted}
+
+	searcher := NewMemoryPostSearcher()
+	searcher.Index(published, draft, private)
+
+	result, err := searcher.SearchPosts(context.Background(), Query{
+		Text:              "go",
+		Statuses:          []constant.BlogStatus{constant.BlogStatusPublished},
+		ExcludeCategories: []primitive.ObjectID{restricted},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 || result.Hits[0].Post.ID != published.ID {
+		t.Fatalf("got %d hits, want only the published public post", result.Total)
+	}
+}
+
+func TestMemoryPostSearcherPaginates(t *testing.T) {
+	searcher := NewMemoryPostSearcher()
+	for i := 0; i < 5; i++ {
+		searcher.Index(newPost("Go post", "", ""))
+	}
+
+	seen := make(map[primitive.ObjectID]bool)
+	for _, page := range []struct {
+		skip, limit int64
+		want        int
+	}{
+		{0, 2, 2},
+		{2, 2, 2},
+		{4, 2, 1},
+		{6, 2, 0},
+	} {
+		result, err := searcher.SearchPosts(context.Background(), Query{Text: "go", Skip: page.skip, Limit: page.limit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Total != 5 {
+			t.Errorf("skip %d: Total = %d, want 5", page.skip, result.Total)
+		}
+		if len(result.Hits) != page.want {
+			t.Errorf("skip %d: got %d hits, want %d", page.skip, len(result.Hits), page.want)
+		}
+		for _, hit := range result.Hits {
+			if seen[hit.Post.ID] {
+				t.Errorf("skip %d: post %s returned twice", page.skip, hit.Post.ID.Hex())
+			}
+			seen[hit.Post.ID] = true
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("pages covered %d posts, want 5", len(seen))
+	}
+}
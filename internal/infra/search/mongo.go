@@ -0,0 +1,66 @@
+package search
+
+import (
+	"context"
+
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/infra/db/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// scoredPost is a post decoded together with its $text relevance score.
+type scoredPost struct {
+	entity.BlogPost `bson:",inline"`
+	Score           float64 `bson:"score"`
+}
+
+// MongoPostSearcher runs $text queries against the blog collection's text
+// index, ranked by MongoDB's textScore.
+type MongoPostSearcher struct {
+	adapter *mongodb.MongoDBAdapter[scoredPost]
+}
+
+func NewMongoPostSearcher(db *mongodb.MongoDBClient) *MongoPostSearcher {
+	return &MongoPostSearcher{
+		adapter: mongodb.NewMongoDBAdapter[scoredPost](
+			db.GetClient(),
+			"capiary",
+			"blog",
+		),
+	}
+}
+
+func (m *MongoPostSearcher) SearchPosts(ctx context.Context, q Query) (Result, error) {
+	filter := bson.M{"$text": bson.M{"$search": q.Text}}
+	if len(q.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": q.Statuses}
+	}
+	if len(q.ExcludeCategories) > 0 {
+		filter["categories"] = bson.M{"$nin": q.ExcludeCategories}
+	}
+
+	total, err := m.adapter.CountDocuments(filter)
+	if err != nil {
+		return Result{}, err
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	findOpts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: -1}}).
+		SetSkip(q.Skip)
+	if q.Limit > 0 {
+		findOpts.SetLimit(q.Limit)
+	}
+	docs, err := m.adapter.Find(filter, findOpts)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Hits: make([]Hit, 0, len(docs)), Total: total}
+	for _, doc := range docs {
+		result.Hits = append(result.Hits, Hit{Post: doc.BlogPost, Score: doc.Score})
+	}
+	return result, nil
+}
@@ -0,0 +1,181 @@
+// Package search provides full-text search over blog posts behind a small
+// interface, so the MongoDB text index can be swapped for an in-process
+// engine where no database is available.
+package search
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Searchable post fields, with the weight a match in each contributes to a
+// post's score. The MongoDB text index declares the same weights.
+const (
+	FieldTitle     = "title"
+	FieldHeading   = "blocks.heading.text"
+	FieldParagraph = "blocks.text.paragraphs.text"
+)
+
+var FieldWeights = map[string]int{
+	FieldTitle:     10,
+	FieldHeading:   5,
+	FieldParagraph: 1,
+}
+
+// Query describes one page of a post search.
+type Query struct {
+	Text              string
+	Statuses          []constant.BlogStatus // only posts in one of these are searched; empty means any
+	ExcludeCategories []primitive.ObjectID  // posts filed under any of these are left out
+	Skip              int64
+	Limit             int64
+}
+
+// Hit is a matching post and its relevance score; higher is better.
+type Hit struct {
+	Post  entity.BlogPost
+	Score float64
+}
+
+// Result is one page of hits, best first, and the total number of matches.
+type Result struct {
+	Hits  []Hit
+	Total int64
+}
+
+// PostSearcher finds blog posts matching free text.
+type PostSearcher interface {
+	SearchPosts(ctx context.Context, q Query) (Result, error)
+}
+
+// Terms splits text into lowercase words, dropping duplicates.
+func Terms(text string) []string {
+	all := words(text)
+	seen := make(map[string]bool, len(all))
+	terms := make([]string, 0, len(all))
+	for _, word := range all {
+		if !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+// fieldTexts returns the searchable text of a post, grouped by field.
+func fieldTexts(post entity.BlogPost) map[string][]string {
+	texts := map[string][]string{FieldTitle: {post.Title}}
+	for _, block := range post.Blocks {
+		if block.Heading != nil {
+			texts[FieldHeading] = append(texts[FieldHeading], block.Heading.Text)
+		}
+		if block.Text != nil {
+			for _, paragraph := range block.Text.Paragraphs {
+				texts[FieldParagraph] = append(texts[FieldParagraph], paragraph.Text)
+			}
+		}
+	}
+	return texts
+}
+
+const (
+	maxSnippets   = 3
+	snippetRadius = 60 // characters of context kept on each side of the first match
+)
+
+// Snippets returns up to three short excerpts of the post around the query
+// terms, best fields first. Text is HTML-escaped and every word starting with
+// a term is wrapped in <mark>, so "run" also highlights "running" the way the
+// stemming text index matches it.
+func Snippets(post entity.BlogPost, text string) []string {
+	terms := Terms(text)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	texts := fieldTexts(post)
+	snippets := []string{}
+	for _, field := range []string{FieldTitle, FieldHeading, FieldParagraph} {
+		for _, t := range texts[field] {
+			if snippet, ok := highlight(t, terms); ok {
+				snippets = append(snippets, snippet)
+				if len(snippets) == maxSnippets {
+					return snippets
+				}
+			}
+		}
+	}
+	return snippets
+}
+
+func highlight(text string, terms []string) (string, bool) {
+	runes := []rune(text)
+	type span struct{ start, end int }
+	var marks []span
+
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		word := strings.ToLower(string(runes[i:j]))
+		for _, term := range terms {
+			if strings.HasPrefix(word, term) {
+				marks = append(marks, span{i, j})
+				break
+			}
+		}
+		i = j
+	}
+	if len(marks) == 0 {
+		return "", false
+	}
+
+	from := marks[0].start - snippetRadius
+	if from < 0 {
+		from = 0
+	}
+	to := marks[0].end + snippetRadius
+	if to > len(runes) {
+		to = len(runes)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, m := range marks {
+		if m.start < from || m.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:m.start])))
+		b.WriteString("<mark>" + html.EscapeString(string(runes[m.start:m.end])) + "</mark>")
+		pos = m.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
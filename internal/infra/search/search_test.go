@@ -0,0 +1,60 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	got := Terms("  Go, go! GOLANG-tips ")
+	want := []string{"go", "golang", "tips"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Terms = %q, want %q", got, want)
+	}
+}
+
+func TestSnippetsHighlightAndEscape(t *testing.T) {
+	post := newPost("Running <fast>", "Why we run", "Nothing to see here.")
+
+	got := Snippets(post, "run")
+	want := []string{
+		"<mark>Running</mark> &lt;fast&gt;",
+		"Why we <mark>run</mark>",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Snippets = %q, want %q", got, want)
+	}
+}
+
+func TestSnippetsTrimLongText(t *testing.T) {
+	long := strings.Repeat("filler ", 30) + "needle" + strings.Repeat(" filler", 30)
+	post := newPost("Untitled", "", long)
+
+	got := Snippets(post, "needle")
+	if len(got) != 1 {
+		t.Fatalf("got %d snippets, want 1", len(got))
+	}
+	snippet := got[0]
+	if !strings.HasPrefix(snippet, "…") || !strings.HasSuffix(snippet, "…") {
+		t.Errorf("snippet %q should be elided on both sides", snippet)
+	}
+	if !strings.Contains(snippet, "<mark>needle</mark>") {
+		t.Errorf("snippet %q does not highlight the match", snippet)
+	}
+	if n := len([]rune(snippet)); n > 2*snippetRadius+len("<mark>needle</mark>")+2 {
+		t.Errorf("snippet is %d characters long", n)
+	}
+}
+
+func TestSnippetsLimit(t *testing.T) {
+	post := newPost("go", "go", "")
+	post.Blocks = append(post.Blocks, newPost("", "go", "go").Blocks...)
+
+	if got := Snippets(post, "go"); len(got) != maxSnippets {
+		t.Errorf("got %d snippets, want %d", len(got), maxSnippets)
+	}
+	if got := Snippets(post, "   "); got != nil {
+		t.Errorf("Snippets without terms = %q, want nil", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
 	"github.com/gin-gonic/gin"
 )
 
@@ -37,7 +38,9 @@ func (am *AuthUserMiddleware) MustAuth() gin.HandlerFunc {
 		}
 
 		userInfo, err := am.GetUserByToken(token)
-		if err != nil || userInfo == nil || userInfo.Status == constant.StatusInactive {
+		// Only approved accounts in good standing get in; this is what makes
+		// pending, suspended, banned and deleted accounts take effect at once.
+		if err != nil || userInfo == nil || userInfo.Status != constant.StatusActive {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 			return
 		}
@@ -46,3 +49,13 @@ func (am *AuthUserMiddleware) MustAuth() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// CurrentUser returns the authenticated user stored on the context by Auth or MustAuth, or nil.
+func CurrentUser(ctx *gin.Context) *entity.User {
+	raw, exists := ctx.Get("userInfo")
+	if !exists {
+		return nil
+	}
+	user, _ := raw.(*entity.User)
+	return user
+}
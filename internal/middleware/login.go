@@ -38,6 +38,14 @@ func (am *AuthUserMiddleware) Login(email, password string) (string, uint64, con
 		return "", 0, "", errors.New("invalid credentials")
 	}
 
+	// Checked after the password so the account status is not disclosed to strangers
+	if user.Status != constant.StatusActive {
+		if user.StatusReason != "" {
+			return "", 0, "", fmt.Errorf("account is %s: %s", user.Status, user.StatusReason)
+		}
+		return "", 0, "", fmt.Errorf("account is %s", user.Status)
+	}
+
 	// Generate JWT token
 	token, err := am.GenerateToken(user)
 	if err != nil {
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole only lets through users whose role is one of roles.
+// It must run after MustAuth, which puts the user on the context.
+func (am *AuthUserMiddleware) RequireRole(roles ...constant.Role) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user := CurrentUser(ctx)
+		if user == nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		if !constant.IsValid(user.Role, roles) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: insufficient role"})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequirePermission only lets through users whose role grants every one of
+// permissions according to constant.RolePermissions.
+// It must run after MustAuth, which puts the user on the context.
+func (am *AuthUserMiddleware) RequirePermission(permissions ...constant.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user := CurrentUser(ctx)
+		if user == nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		for _, permission := range permissions {
+			if !constant.HasPermission(user.Role, permission) {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: missing permission " + string(permission)})
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
@@ -4,18 +4,46 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/capigiba/capiary/internal/domain/constant"
 	"github.com/capigiba/capiary/internal/domain/entity"
 	"github.com/capigiba/capiary/internal/infra/db/mongodb"
 	"github.com/capigiba/capiary/internal/infra/db/query"
+	"github.com/capigiba/capiary/internal/infra/search"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BlogPostSchema lists the post fields clients may filter, sort or project on.
+var BlogPostSchema = query.NewSchema(
+	query.FieldSpec{Name: "id", Column: "_id", Type: query.FieldObjectID, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "author_id", Type: query.FieldInt, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "editor_ids", Type: query.FieldInt, Filterable: true},
+	query.FieldSpec{Name: "categories", Type: query.FieldObjectID, Filterable: true},
+	query.FieldSpec{Name: "title", Type: query.FieldString, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "slug", Type: query.FieldString, Filterable: true},
+	query.FieldSpec{Name: "blocks"},
+	query.FieldSpec{Name: "status", Type: query.FieldEnum, Values: query.EnumValues(constant.AllBlogStatus), Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "version", Type: query.FieldInt, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "publish_at", Type: query.FieldTime, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "published_at", Type: query.FieldTime, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "created_at", Type: query.FieldTime, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "updated_at", Type: query.FieldTime, Filterable: true, Sortable: true},
 )
 
 type BlogPostRepository interface {
 	Add(ctx context.Context, post entity.BlogPost) (string, error)
-	UpdateByQuery(ctx context.Context, filter bson.M, update entity.BlogPost) error
+	UpdateByQuery(ctx context.Context, filter bson.M, update entity.BlogPost) (int64, error)
 	FindByQuery(ctx context.Context, opts query.QueryOptions) ([]entity.BlogPost, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.BlogPost, error)
+	FindOneByQuery(ctx context.Context, filter bson.M) (*entity.BlogPost, error)
 	LoadAll(ctx context.Context) ([]entity.BlogPost, error)
-	UpdateFieldsByQuery(ctx context.Context, filter bson.M, fields bson.M) error
+	UpdateFieldsByQuery(ctx context.Context, filter bson.M, fields bson.M) (int64, error)
+	FindOneAndUpdateFields(ctx context.Context, filter bson.M, fields bson.M, sort bson.D) (*entity.BlogPost, error)
+	UpdateManyByQuery(ctx context.Context, filter bson.M, update interface{}) (int64, error)
+	CountByQuery(ctx context.Context, filter bson.M) (int64, error)
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	IndexedRepository
 }
 
 type blogPostRepository struct {
@@ -28,10 +56,66 @@ func NewBlogPostRepository(db *mongodb.MongoDBClient) BlogPostRepository {
 			db.GetClient(),
 			"capiary",
 			"blog",
+		).WithIndexes(
+			// Listings sort on created_at with _id as the tie-breaker.
+			mongodb.IndexSpec{
+				Name: "created_at_id",
+				Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			},
+			mongodb.IndexSpec{
+				Name: "status_created_at_id",
+				Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			},
+			mongodb.IndexSpec{
+				Name: "author_id_created_at",
+				Keys: bson.D{{Key: "author_id", Value: 1}, {Key: "created_at", Value: -1}},
+			},
+			mongodb.IndexSpec{
+				Name: "categories_created_at",
+				Keys: bson.D{{Key: "categories", Value: 1}, {Key: "created_at", Value: -1}},
+			},
+			mongodb.IndexSpec{
+				Name: "title",
+				Keys: bson.D{{Key: "title", Value: 1}},
+			},
+			// The publish scheduler claims scheduled posts in publish_at order.
+			mongodb.IndexSpec{
+				Name: "status_publish_at",
+				Keys: bson.D{{Key: "status", Value: 1}, {Key: "publish_at", Value: 1}},
+			},
+			// Backs search.MongoPostSearcher.
+			mongodb.IndexSpec{
+				Name: "text_search",
+				Keys: bson.D{
+					{Key: search.FieldTitle, Value: "text"},
+					{Key: search.FieldHeading, Value: "text"},
+					{Key: search.FieldParagraph, Value: "text"},
+				},
+				Weights: bson.M{
+					search.FieldTitle:     search.FieldWeights[search.FieldTitle],
+					search.FieldHeading:   search.FieldWeights[search.FieldHeading],
+					search.FieldParagraph: search.FieldWeights[search.FieldParagraph],
+				},
+			},
+			// Posts created before slugs existed have none and are left out.
+			mongodb.IndexSpec{
+				Name:          "uniq_slug",
+				Keys:          bson.D{{Key: "slug", Value: 1}},
+				Unique:        true,
+				PartialFilter: bson.M{"slug": bson.M{"$gt": ""}},
+			},
 		),
 	}
 }
 
+func (r *blogPostRepository) EnsureIndexes(ctx context.Context) (mongodb.IndexDiff, error) {
+	return r.adapter.EnsureIndexes(ctx)
+}
+
+func (r *blogPostRepository) DiffIndexes(ctx context.Context) (mongodb.IndexDiff, error) {
+	return r.adapter.DiffIndexes(ctx)
+}
+
 func (r *blogPostRepository) Add(ctx context.Context, post entity.BlogPost) (string, error) {
 	oid, err := r.adapter.InsertOne(post)
 	if err != nil {
@@ -40,19 +124,48 @@ func (r *blogPostRepository) Add(ctx context.Context, post entity.BlogPost) (str
 	return oid.Hex(), nil
 }
 
-func (r *blogPostRepository) UpdateByQuery(ctx context.Context, filter bson.M, update entity.BlogPost) error {
+func (r *blogPostRepository) UpdateByQuery(ctx context.Context, filter bson.M, update entity.BlogPost) (int64, error) {
 	return r.adapter.UpdateOne(filter, update)
 }
 
-func (r *blogPostRepository) UpdateFieldsByQuery(ctx context.Context, filter bson.M, fields bson.M) error {
+func (r *blogPostRepository) UpdateFieldsByQuery(ctx context.Context, filter bson.M, fields bson.M) (int64, error) {
 	return r.adapter.UpdateOne(filter, fields)
 }
 
+func (r *blogPostRepository) FindOneAndUpdateFields(ctx context.Context, filter bson.M, fields bson.M, sort bson.D) (*entity.BlogPost, error) {
+	return r.adapter.FindOneAndUpdate(filter, fields, options.FindOneAndUpdate().SetSort(sort))
+}
+
+// UpdateManyByQuery applies a full update document, or an aggregation
+// pipeline, to every matching post.
+func (r *blogPostRepository) UpdateManyByQuery(ctx context.Context, filter bson.M, update interface{}) (int64, error) {
+	return r.adapter.UpdateMany(filter, update)
+}
+
+func (r *blogPostRepository) CountByQuery(ctx context.Context, filter bson.M) (int64, error) {
+	return r.adapter.CountDocuments(filter)
+}
+
 func (r *blogPostRepository) FindByQuery(ctx context.Context, opts query.QueryOptions) ([]entity.BlogPost, error) {
 	fmt.Println(opts)
 	return r.adapter.FindWithQuery(opts)
 }
 
+func (r *blogPostRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.BlogPost, error) {
+	return r.adapter.FindOne(bson.M{"_id": id})
+}
+
+// DeleteByID permanently removes a post. Posts are normally soft-deleted; this
+// only undoes a create that could not be completed.
+func (r *blogPostRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.adapter.DeleteOne(bson.M{"_id": id})
+	return err
+}
+
+func (r *blogPostRepository) FindOneByQuery(ctx context.Context, filter bson.M) (*entity.BlogPost, error) {
+	return r.adapter.FindOne(filter)
+}
+
 func (r *blogPostRepository) LoadAll(ctx context.Context) ([]entity.BlogPost, error) {
 	return r.adapter.Find(bson.M{})
 }
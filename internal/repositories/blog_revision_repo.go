@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/infra/db/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BlogRevisionRepository stores revisions append-only: there is deliberately no update or delete.
+type BlogRevisionRepository interface {
+	Add(ctx context.Context, revision entity.BlogRevision) (string, error)
+	FindByID(ctx context.Context, postID, revisionID primitive.ObjectID) (*entity.BlogRevision, error)
+	FindByPostID(ctx context.Context, postID primitive.ObjectID) ([]entity.BlogRevision, error)
+	IndexedRepository
+}
+
+type blogRevisionRepository struct {
+	adapter *mongodb.MongoDBAdapter[entity.BlogRevision]
+}
+
+func NewBlogRevisionRepository(db *mongodb.MongoDBClient) BlogRevisionRepository {
+	return &blogRevisionRepository{
+		adapter: mongodb.NewMongoDBAdapter[entity.BlogRevision](
+			db.GetClient(),
+			"capiary",
+			"blog_revisions",
+		).WithIndexes(
+			mongodb.IndexSpec{
+				Name: "post_id_created_at_id",
+				Keys: bson.D{{Key: "post_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			},
+		),
+	}
+}
+
+func (r *blogRevisionRepository) EnsureIndexes(ctx context.Context) (mongodb.IndexDiff, error) {
+	return r.adapter.EnsureIndexes(ctx)
+}
+
+func (r *blogRevisionRepository) DiffIndexes(ctx context.Context) (mongodb.IndexDiff, error) {
+	return r.adapter.DiffIndexes(ctx)
+}
+
+func (r *blogRevisionRepository) Add(ctx context.Context, revision entity.BlogRevision) (string, error) {
+	oid, err := r.adapter.InsertOne(revision)
+	if err != nil {
+		return "", err
+	}
+	return oid.Hex(), nil
+}
+
+func (r *blogRevisionRepository) FindByID(ctx context.Context, postID, revisionID primitive.ObjectID) (*entity.BlogRevision, error) {
+	return r.adapter.FindOne(bson.M{"_id": revisionID, "post_id": postID})
+}
+
+func (r *blogRevisionRepository) FindByPostID(ctx context.Context, postID primitive.ObjectID) ([]entity.BlogRevision, error) {
+	sort := bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	return r.adapter.Find(bson.M{"post_id": postID}, options.Find().SetSort(sort))
+}
@@ -4,18 +4,37 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/capigiba/capiary/internal/domain/constant"
 	"github.com/capigiba/capiary/internal/domain/entity"
 	"github.com/capigiba/capiary/internal/infra/db/mongodb"
 	"github.com/capigiba/capiary/internal/infra/db/query"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CategorySchema lists the category fields clients may filter, sort or project on.
+var CategorySchema = query.NewSchema(
+	query.FieldSpec{Name: "id", Column: "_id", Type: query.FieldObjectID, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "name", Type: query.FieldString, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "description", Type: query.FieldString, Filterable: true},
+	query.FieldSpec{Name: "access", Type: query.FieldString, Filterable: true},
+	query.FieldSpec{Name: "status", Type: query.FieldEnum, Values: query.EnumValues(constant.AllCategoryStatus), Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "parent_id", Type: query.FieldObjectID, Filterable: true},
+	query.FieldSpec{Name: "ancestors", Type: query.FieldObjectID, Filterable: true},
+	query.FieldSpec{Name: "created_at", Type: query.FieldTime, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "updated_at", Type: query.FieldTime, Filterable: true, Sortable: true},
 )
 
 type CategoryRepository interface {
 	Add(ctx context.Context, post entity.Category) (string, error)
-	UpdateByQuery(ctx context.Context, filter bson.M, update entity.Category) error
+	UpdateByQuery(ctx context.Context, filter bson.M, update entity.Category) (int64, error)
 	FindByQuery(ctx context.Context, opts query.QueryOptions) ([]entity.Category, error)
-	UpdateFieldsByQuery(ctx context.Context, filter bson.M, fields bson.M) error
+	UpdateFieldsByQuery(ctx context.Context, filter bson.M, fields bson.M) (int64, error)
 	LoadAll(ctx context.Context) ([]entity.Category, error)
+	FindByFilter(ctx context.Context, filter bson.M) ([]entity.Category, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Category, error)
+	CountByQuery(ctx context.Context, filter bson.M) (int64, error)
+	IndexedRepository
 }
 
 type categoryRepository struct {
@@ -28,10 +47,39 @@ func NewCategoryRepository(db *mongodb.MongoDBClient) CategoryRepository {
 			db.GetClient(),
 			"capiary",
 			"category",
+		).WithIndexes(
+			// Soft-deleted categories have a null normalized_name, which frees
+			// their name for reuse.
+			mongodb.IndexSpec{
+				Name:          "uniq_normalized_name",
+				Keys:          bson.D{{Key: "normalized_name", Value: 1}},
+				Unique:        true,
+				PartialFilter: bson.M{"normalized_name": bson.M{"$type": "string"}},
+			},
+			mongodb.IndexSpec{
+				Name: "parent_id",
+				Keys: bson.D{{Key: "parent_id", Value: 1}},
+			},
+			mongodb.IndexSpec{
+				Name: "ancestors",
+				Keys: bson.D{{Key: "ancestors", Value: 1}},
+			},
+			mongodb.IndexSpec{
+				Name: "status_name",
+				Keys: bson.D{{Key: "status", Value: 1}, {Key: "name", Value: 1}},
+			},
 		),
 	}
 }
 
+func (r *categoryRepository) EnsureIndexes(ctx context.Context) (mongodb.IndexDiff, error) {
+	return r.adapter.EnsureIndexes(ctx)
+}
+
+func (r *categoryRepository) DiffIndexes(ctx context.Context) (mongodb.IndexDiff, error) {
+	return r.adapter.DiffIndexes(ctx)
+}
+
 func (r *categoryRepository) Add(ctx context.Context, post entity.Category) (string, error) {
 	oid, err := r.adapter.InsertOne(post)
 	if err != nil {
@@ -40,7 +88,7 @@ func (r *categoryRepository) Add(ctx context.Context, post entity.Category) (str
 	return oid.Hex(), nil
 }
 
-func (r *categoryRepository) UpdateByQuery(ctx context.Context, filter bson.M, update entity.Category) error {
+func (r *categoryRepository) UpdateByQuery(ctx context.Context, filter bson.M, update entity.Category) (int64, error) {
 	return r.adapter.UpdateOne(filter, update)
 }
 
@@ -49,12 +97,31 @@ func (r *categoryRepository) FindByQuery(ctx context.Context, opts query.QueryOp
 	return r.adapter.FindWithQuery(opts)
 }
 
-func (r *categoryRepository) UpdateFieldsByQuery(ctx context.Context, filter bson.M, fields bson.M) error {
+func (r *categoryRepository) UpdateFieldsByQuery(ctx context.Context, filter bson.M, fields bson.M) (int64, error) {
 	return r.adapter.UpdateOne(filter, fields)
 }
 
+func (r *categoryRepository) FindByFilter(ctx context.Context, filter bson.M) ([]entity.Category, error) {
+	return r.adapter.Find(filter)
+}
+
+func (r *categoryRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Category, error) {
+	return r.adapter.FindOne(bson.M{"_id": id})
+}
+
+func (r *categoryRepository) CountByQuery(ctx context.Context, filter bson.M) (int64, error) {
+	return r.adapter.CountDocuments(filter)
+}
+
 func (r *categoryRepository) LoadAll(ctx context.Context) ([]entity.Category, error) {
 	loadAllOpts := query.QueryOptions{
+		Filters: []query.Filter{
+			{
+				Field:    "status",
+				Operator: query.OpNotEqual,
+				Value:    constant.CategoryStatusDeleted,
+			},
+		},
 		Sorts: []query.Sort{
 			{
 				Field: "name",
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/capigiba/capiary/internal/infra/db/mongodb"
+)
+
+// IndexedRepository is implemented by the MongoDB repositories, each of which
+// declares the indexes its queries rely on.
+type IndexedRepository interface {
+	// EnsureIndexes creates missing indexes and rebuilds changed ones.
+	EnsureIndexes(ctx context.Context) (mongodb.IndexDiff, error)
+	// DiffIndexes reports how the collection differs from its declaration without changing it.
+	DiffIndexes(ctx context.Context) (mongodb.IndexDiff, error)
+}
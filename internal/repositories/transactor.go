@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/capigiba/capiary/internal/infra/db/postgres"
+	"github.com/jmoiron/sqlx"
+)
+
+// Transactor runs a unit of work in one Postgres transaction. Postgres
+// repository calls made with the context fn receives take part in it.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type postgresTransactor struct {
+	db *sqlx.DB
+}
+
+// NewTransactor returns a Transactor for the given Postgres connection.
+func NewTransactor(db *sqlx.DB) Transactor {
+	return &postgresTransactor{db: db}
+}
+
+func (t *postgresTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return postgres.WithinTx(ctx, t.db, fn)
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/infra/db/postgres"
+	"github.com/capigiba/capiary/internal/infra/db/query"
+	"github.com/jmoiron/sqlx"
+)
+
+// UserAuditRepository stores the append-only log of admin actions on user
+// accounts. Entries are never updated or deleted.
+type UserAuditRepository interface {
+	Add(ctx context.Context, entry *entity.UserAuditEntry) error
+	FindByQuery(ctx context.Context, opts query.QueryOptions) ([]entity.UserAuditEntry, error)
+	CountByQuery(ctx context.Context, opts query.QueryOptions) (int64, error)
+}
+
+type userAuditRepo struct {
+	adapter *postgres.PostgresAdapter[entity.UserAuditEntry]
+}
+
+// NewUserAuditRepo returns a Postgres-backed UserAuditRepository.
+func NewUserAuditRepo(db *sqlx.DB) UserAuditRepository {
+	return &userAuditRepo{
+		adapter: postgres.NewPostgresAdapter[entity.UserAuditEntry](db, "user_audit_log", "id"),
+	}
+}
+
+// Add appends an entry and sets its ID.
+func (r *userAuditRepo) Add(ctx context.Context, entry *entity.UserAuditEntry) error {
+	created, err := r.adapter.Insert(ctx, *entry)
+	if err != nil {
+		return err
+	}
+	entry.ID = created.ID
+	return nil
+}
+
+// FindByQuery lists audit entries.
+func (r *userAuditRepo) FindByQuery(ctx context.Context, opts query.QueryOptions) ([]entity.UserAuditEntry, error) {
+	return r.adapter.FindWithQuery(ctx, opts)
+}
+
+// CountByQuery counts the entries FindByQuery would return without pagination.
+func (r *userAuditRepo) CountByQuery(ctx context.Context, opts query.QueryOptions) (int64, error) {
+	return r.adapter.Count(ctx, opts)
+}
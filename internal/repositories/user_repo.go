@@ -2,16 +2,33 @@ package repositories
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/capigiba/capiary/internal/domain/constant"
 	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/infra/db/postgres"
+	"github.com/capigiba/capiary/internal/infra/db/query"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" // Postgres driver
 )
 
+// UserSchema lists the user fields clients may filter, sort or project on.
+// The password hash is deliberately absent.
+var UserSchema = query.NewSchema(
+	query.FieldSpec{Name: "id", Type: query.FieldInt, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "first_name", Type: query.FieldString, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "last_name", Type: query.FieldString, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "username", Type: query.FieldString, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "email", Type: query.FieldString, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "status", Type: query.FieldEnum, Values: query.EnumValues(constant.AllAccountStatus), Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "role", Type: query.FieldEnum, Values: query.EnumValues(constant.AllRoles), Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "avatar", Type: query.FieldString},
+	query.FieldSpec{Name: "wallet_balance", Type: query.FieldInt, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "created_at", Type: query.FieldTime, Filterable: true, Sortable: true},
+	query.FieldSpec{Name: "updated_at", Type: query.FieldTime, Filterable: true, Sortable: true},
+)
+
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *entity.User) error
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
@@ -22,138 +39,71 @@ type UserRepository interface {
 	GetAllUsers(ctx context.Context) ([]entity.User, error)
 	UpdateUserPassword(ctx context.Context, userID uint64, hashedPassword string) error
 	UpdateUserAvatar(ctx context.Context, userID uint64, avatarPath, avatarFolder string) error
+	FindByQuery(ctx context.Context, opts query.QueryOptions) ([]entity.User, error)
+	CountByQuery(ctx context.Context, opts query.QueryOptions) (int64, error)
 }
 
 type userRepo struct {
-	db *sqlx.DB
+	adapter *postgres.PostgresAdapter[entity.User]
 }
 
 // NewUserRepo returns a concrete implementation of UserRepository backed by Postgres/sqlx.
 func NewUserRepo(db *sqlx.DB) UserRepository {
-	return &userRepo{db: db}
+	return &userRepo{
+		adapter: postgres.NewPostgresAdapter[entity.User](db, "users", "id").
+			WithSoftDelete("status", constant.StatusDeleted),
+	}
+}
+
+func byID(userID uint64) query.Filter {
+	return query.Filter{Field: "id", Operator: query.OpEqual, Value: userID}
 }
 
-// CreateUser inserts a new user into the database.
+// CreateUser inserts a new user into the database and sets its ID.
 func (r *userRepo) CreateUser(ctx context.Context, user *entity.User) error {
-	query := `
-		INSERT INTO users (
-			first_name, last_name, username, email, password,
-			status, role, avatar, avatar_folder, wallet_balance,
-			created_at, updated_at
-		)
-		VALUES ($1, $2, $3, $4, $5,
-		        $6, $7, $8, $9, $10,
-		        $11, $12)
-		RETURNING id
-	`
-	err := r.db.QueryRowContext(
-		ctx,
-		query,
-		user.FirstName,
-		user.LastName,
-		user.UserName,
-		user.Email,
-		user.Password,
-		user.Status,
-		user.Role,
-		user.Avatar,
-		user.AvatarFolder,
-		user.WalletBalance,
-		user.CreatedAt,
-		user.UpdatedAt,
-	).Scan(&user.ID)
-	return err
+	created, err := r.adapter.Insert(ctx, *user)
+	if err != nil {
+		return err
+	}
+	user.ID = created.ID
+	return nil
 }
 
 // GetUserByEmail retrieves a user by their email address.
 func (r *userRepo) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
-	query := `
-		SELECT 
-			id, first_name, last_name, username, email,
-			password, status, role, avatar, avatar_folder,
-			wallet_balance, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
-	var user entity.User
-	err := r.db.GetContext(ctx, &user, query, email)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return &user, nil
+	return r.adapter.FindOne(ctx, query.Filter{Field: "email", Operator: query.OpEqual, Value: email})
 }
 
 // GetUserByID retrieves a user by their ID.
 func (r *userRepo) GetUserByID(ctx context.Context, userID uint64) (*entity.User, error) {
-	query := `
-		SELECT 
-			id, first_name, last_name, username, email,
-			password, status, role, avatar, avatar_folder,
-			wallet_balance, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
-	var user entity.User
-	err := r.db.GetContext(ctx, &user, query, userID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return &user, nil
+	return r.adapter.FindOne(ctx, byID(userID))
 }
 
 // UpdateUser updates user information (except password/avatar).
 func (r *userRepo) UpdateUser(ctx context.Context, user *entity.User) error {
-	query := `
-		UPDATE users
-		SET 
-			first_name     = $1,
-			last_name      = $2,
-			username       = $3,
-			email          = $4,
-			status         = $5,
-			role           = $6,
-			wallet_balance = $7,
-			updated_at     = $8
-		WHERE id = $9
-	`
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
-		user.FirstName,
-		user.LastName,
-		user.UserName,
-		user.Email,
-		user.Status,
-		user.Role,
-		user.WalletBalance,
-		user.UpdatedAt,
-		user.ID,
-	)
+	_, err := r.adapter.UpdateFields(ctx, map[string]interface{}{
+		"first_name":     user.FirstName,
+		"last_name":      user.LastName,
+		"username":       user.UserName,
+		"email":          user.Email,
+		"status":         user.Status,
+		"status_reason":  user.StatusReason,
+		"role":           user.Role,
+		"wallet_balance": user.WalletBalance,
+		"updated_at":     user.UpdatedAt,
+	}, byID(user.ID))
 	return err
 }
 
 // SoftDeleteUser sets the user status to "deleted".
 func (r *userRepo) SoftDeleteUser(ctx context.Context, userID uint64) error {
-	query := `
-		UPDATE users 
-		SET status = $1 
-		WHERE id = $2
-	`
-	_, err := r.db.ExecContext(ctx, query, constant.StatusDeleted, userID)
+	_, err := r.adapter.SoftDelete(ctx, byID(userID))
 	return err
 }
 
 // DeleteUser permanently removes the user from the database.
 func (r *userRepo) DeleteUser(ctx context.Context, userID uint64) error {
-	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, userID)
-	if err != nil {
+	if _, err := r.adapter.Delete(ctx, byID(userID)); err != nil {
 		return fmt.Errorf("failed to delete user with ID %d: %v", userID, err)
 	}
 	return nil
@@ -161,44 +111,35 @@ func (r *userRepo) DeleteUser(ctx context.Context, userID uint64) error {
 
 // UpdateUserPassword updates the hashed password for a user.
 func (r *userRepo) UpdateUserPassword(ctx context.Context, userID uint64, hashedPassword string) error {
-	query := `
-		UPDATE users
-		SET 
-			password = $1,
-			updated_at = $2
-		WHERE id = $3
-	`
-	_, err := r.db.ExecContext(ctx, query, hashedPassword, time.Now(), userID)
+	_, err := r.adapter.UpdateFields(ctx, map[string]interface{}{
+		"password":   hashedPassword,
+		"updated_at": time.Now(),
+	}, byID(userID))
 	return err
 }
 
 // UpdateUserAvatar updates the user's avatar and avatar folder.
 func (r *userRepo) UpdateUserAvatar(ctx context.Context, userID uint64, avatarPath, avatarFolder string) error {
-	query := `
-		UPDATE users
-		SET 
-			avatar = $1,
-			avatar_folder = $2,
-			updated_at = $3
-		WHERE id = $4
-	`
-	_, err := r.db.ExecContext(ctx, query, avatarPath, avatarFolder, time.Now(), userID)
+	_, err := r.adapter.UpdateFields(ctx, map[string]interface{}{
+		"avatar":        avatarPath,
+		"avatar_folder": avatarFolder,
+		"updated_at":    time.Now(),
+	}, byID(userID))
 	return err
 }
 
 // GetAllUsers retrieves all users from the database.
 func (r *userRepo) GetAllUsers(ctx context.Context) ([]entity.User, error) {
-	query := `
-		SELECT 
-			id, first_name, last_name, username, email,
-			password, status, role, avatar, avatar_folder,
-			wallet_balance, created_at, updated_at
-		FROM users
-	`
-	var users []entity.User
-	err := r.db.SelectContext(ctx, &users, query)
-	if err != nil {
-		return nil, err
-	}
-	return users, nil
+	return r.adapter.FindWithQuery(ctx, query.QueryOptions{})
+}
+
+// FindByQuery lists users with the same filter/where/sort/fields/pagination
+// options the Mongo repositories take.
+func (r *userRepo) FindByQuery(ctx context.Context, opts query.QueryOptions) ([]entity.User, error) {
+	return r.adapter.FindWithQuery(ctx, opts)
+}
+
+// CountByQuery counts the users FindByQuery would return without pagination.
+func (r *userRepo) CountByQuery(ctx context.Context, opts query.QueryOptions) (int64, error) {
+	return r.adapter.Count(ctx, opts)
 }
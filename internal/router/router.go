@@ -1,6 +1,7 @@
 package router
 
 import (
+	"github.com/capigiba/capiary/internal/domain/constant"
 	handler "github.com/capigiba/capiary/internal/handler/rest/v1"
 	"github.com/capigiba/capiary/internal/middleware"
 	"github.com/gin-gonic/gin"
@@ -42,27 +43,80 @@ func (a *AppRouter) RegisterUserRoutes(r *gin.RouterGroup) {
 	{
 		protected.PUT("/:user_id/change-password", a.userController.ChangePassword)
 	}
+
+	// Every change made here is recorded in the user audit log; see UserService.
+	admins := r.Group("/users")
+	admins.Use(a.authMiddleware.MustAuth(), a.authMiddleware.RequirePermission(constant.PermissionUserManage))
+	{
+		admins.GET("/list", a.userController.FindUsersHandler)
+		admins.GET("/:user_id", a.userController.GetUserHandler)
+		admins.GET("/:user_id/audit", a.userController.UserAuditHandler)
+		admins.POST("/:user_id/approve", a.userController.ModerateUserHandler(constant.UserAuditApprove))
+		admins.POST("/:user_id/suspend", a.userController.ModerateUserHandler(constant.UserAuditSuspend))
+		admins.POST("/:user_id/ban", a.userController.ModerateUserHandler(constant.UserAuditBan))
+		admins.POST("/:user_id/reinstate", a.userController.ModerateUserHandler(constant.UserAuditReinstate))
+		admins.PUT("/:user_id/role", a.userController.ChangeUserRoleHandler)
+		admins.DELETE("/:user_id", a.userController.DeleteUserHandler)
+	}
 }
 
 func (a *AppRouter) RegisterBlogRoutes(r *gin.RouterGroup) {
-	protected := r.Group("/blog")
-	protected.Use(a.authMiddleware.MustAuth())
+	auth := a.authMiddleware
+
+	readers := r.Group("/blog")
+	readers.Use(auth.MustAuth(), auth.RequirePermission(constant.PermissionPostRead))
+	{
+		readers.GET("/posts", a.blogController.FindBlogPostsHandler)
+		readers.GET("/posts/all", a.blogController.LoadAllPostsHandler)
+		readers.GET("/posts/search", a.blogController.SearchBlogPostsHandler)
+		readers.GET("/posts/by-slug/:slug", a.blogController.GetBlogPostBySlugHandler)
+		readers.GET("/posts/:id", a.blogController.GetBlogPostHandler)
+		readers.GET("/posts/:id/revisions", a.blogController.ListRevisionsHandler)
+		readers.GET("/posts/:id/revisions/diff", a.blogController.DiffRevisionsHandler)
+	}
+
+	// Writers still have to own or co-author a post to change it; see BlogPostService.
+	writers := r.Group("/blog")
+	writers.Use(auth.MustAuth(), auth.RequirePermission(constant.PermissionPostWrite))
 	{
-		protected.POST("/posts", a.blogController.CreateBlogPostHandler)
-		protected.GET("/posts", a.blogController.FindBlogPostsHandler)
-		protected.PUT("/posts", a.blogController.UpdateBlogPostHandler)
-		protected.GET("/posts/all", a.blogController.LoadAllPostsHandler)
-		protected.DELETE("/posts", a.blogController.DeleteBlogPostHandler)
+		writers.POST("/posts", a.blogController.CreateBlogPostHandler)
+		writers.PUT("/posts/:id", a.blogController.UpdateBlogPostByIDHandler)
+		writers.PATCH("/posts/:id", a.blogController.PatchBlogPostHandler)
+		writers.DELETE("/posts/:id", a.blogController.DeleteBlogPostByIDHandler)
+		writers.POST("/posts/:id/transition", a.blogController.TransitionBlogPostHandler)
+		writers.PUT("/posts/:id/editors", a.blogController.UpdatePostEditorsHandler)
+		writers.POST("/posts/:id/revisions/:revision_id/restore", a.blogController.RestoreRevisionHandler)
+	}
+
+	// Filter-based bulk routes can touch many posts at once, so only admins may use them.
+	bulk := r.Group("/blog")
+	bulk.Use(auth.MustAuth(), auth.RequirePermission(constant.PermissionPostBulkWrite))
+	{
+		bulk.PUT("/posts", a.blogController.UpdateBlogPostHandler)
+		bulk.DELETE("/posts", a.blogController.DeleteBlogPostHandler)
 	}
 }
 
 func (a *AppRouter) RegisterCategoryRoutes(r *gin.RouterGroup) {
-	protected := r.Group("/categories")
-	protected.Use(a.authMiddleware.MustAuth())
+	auth := a.authMiddleware
+
+	readers := r.Group("/categories")
+	readers.Use(auth.MustAuth(), auth.RequirePermission(constant.PermissionCategoryRead))
+	{
+		readers.GET("/list", a.categoryController.FindCategoriesHandler)
+		readers.GET("/tree", a.categoryController.CategoryTreeHandler)
+		readers.GET("/:id/posts", a.blogController.FindPostsByCategoryHandler)
+	}
+
+	managers := r.Group("/categories")
+	managers.Use(auth.MustAuth(), auth.RequirePermission(constant.PermissionCategoryManage))
 	{
-		protected.POST("/create", a.categoryController.CreateCategoryHandler)
-		protected.GET("/list", a.categoryController.FindCategoriesHandler)
-		protected.PUT("/update", a.categoryController.UpdateCategoryHandler)
+		managers.POST("/create", a.categoryController.CreateCategoryHandler)
+		managers.PUT("/update", a.categoryController.UpdateCategoryHandler)
+		managers.DELETE("/:id", a.categoryController.DeleteCategoryHandler)
+		managers.POST("/:id/archive", a.categoryController.ArchiveCategoryHandler)
+		managers.POST("/:id/unarchive", a.categoryController.UnarchiveCategoryHandler)
+		managers.POST("/:id/merge", a.categoryController.MergeCategoryHandler)
 	}
 }
 
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/infra/db/query"
+	"github.com/capigiba/capiary/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// isPostOwner reports whether the user wrote the post or is an admin.
+// Owners may delete the post and manage its editors.
+func isPostOwner(user *entity.User, post *entity.BlogPost) bool {
+	if user == nil {
+		return false
+	}
+	return user.Role == constant.RoleAdmin || (post.AuthorID != 0 && post.AuthorID == user.ID)
+}
+
+// canEditPost reports whether the user may change the post's content:
+// its owners plus every co-author listed in EditorIDs.
+func canEditPost(user *entity.User, post *entity.BlogPost) bool {
+	if isPostOwner(user, post) {
+		return true
+	}
+	return user != nil && constant.IsValid(user.ID, post.EditorIDs)
+}
+
+// readableStatuses are the statuses every reader may see. Active is the
+// legacy equivalent of published, from before the editorial workflow.
+var readableStatuses = []constant.BlogStatus{constant.BlogStatusPublished, constant.BlogStatusActive}
+
+// canSeePost reports whether the user may see the post at all: published
+// posts are visible to everyone, unpublished ones only to those who may edit
+// them. Deleted posts are visible to no one.
+func canSeePost(user *entity.User, post *entity.BlogPost) bool {
+	if post.Status == constant.BlogStatusDeleted {
+		return false
+	}
+	return constant.IsValid(post.Status, readableStatuses) || canEditPost(user, post)
+}
+
+// visibilityCondition is canSeePost as a query condition, for listings.
+func visibilityCondition(user *entity.User) *query.Condition {
+	notDeleted := query.Condition{Filter: &query.Filter{Field: "status", Operator: query.OpNotEqual, Value: constant.BlogStatusDeleted}}
+	if user != nil && user.Role == constant.RoleAdmin {
+		return &notDeleted
+	}
+
+	readable := query.Condition{Filter: &query.Filter{Field: "status", Operator: query.OpIn, Value: readableStatuses}}
+	if user == nil {
+		return &readable
+	}
+	return &query.Condition{Op: query.LogicAnd, Children: []query.Condition{
+		notDeleted,
+		{Op: query.LogicOr, Children: []query.Condition{
+			readable,
+			{Filter: &query.Filter{Field: "author_id", Operator: query.OpEqual, Value: user.ID}},
+			{Filter: &query.Filter{Field: "editor_ids", Operator: query.OpEqual, Value: user.ID}},
+		}},
+	}}
+}
+
+func authorizeEdit(c *gin.Context, post *entity.BlogPost) error {
+	if !canEditPost(middleware.CurrentUser(c), post) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func authorizeOwner(c *gin.Context, post *entity.BlogPost) error {
+	if !isPostOwner(middleware.CurrentUser(c), post) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func authorizePermission(c *gin.Context, permission constant.Permission) error {
+	user := middleware.CurrentUser(c)
+	if user == nil || !constant.HasPermission(user.Role, permission) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// restrictedCategoryIDs returns the IDs of categories whose Access list excludes
+// the current user's role. Categories with an empty Access list are public and
+// admins can read everything.
+func (s *blogPostService) restrictedCategoryIDs(c *gin.Context) ([]primitive.ObjectID, error) {
+	var role constant.Role
+	if user := middleware.CurrentUser(c); user != nil {
+		role = user.Role
+	}
+	if role == constant.RoleAdmin {
+		return nil, nil
+	}
+
+	filter := bson.M{"access": bson.M{
+		"$type": "array",
+		"$ne":   bson.A{},
+		"$nin":  bson.A{role},
+	}}
+	categories, err := s.categoryRepo.FindByFilter(c.Request.Context(), filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load category access: %w", err)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(categories))
+	for _, category := range categories {
+		ids = append(ids, category.ID)
+	}
+	return ids, nil
+}
+
+// authorizeRead rejects users whose role may not read one of the post's
+// categories. The post's own author and editors can always read it. To anyone
+// else an unpublished post does not exist, so its ID or slug reveals nothing.
+func (s *blogPostService) authorizeRead(c *gin.Context, post *entity.BlogPost) error {
+	user := middleware.CurrentUser(c)
+	if canEditPost(user, post) {
+		return nil
+	}
+	if !canSeePost(user, post) {
+		return ErrPostNotFound
+	}
+
+	restricted, err := s.restrictedCategoryIDs(c)
+	if err != nil {
+		return err
+	}
+	if inRestrictedCategory(*post, restricted) {
+		return ErrReadForbidden
+	}
+	return nil
+}
+
+func inRestrictedCategory(post entity.BlogPost, restricted []primitive.ObjectID) bool {
+	for _, categoryID := range post.Categories {
+		if constant.IsValid(categoryID, restricted) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCategories drops duplicate IDs and makes sure every remaining one
+// refers to an existing category. Archived categories take no new posts, but
+// the ones listed in current (the post's existing categories) may be kept.
+func (s *blogPostService) validateCategories(ctx context.Context, ids, current []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	unique := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if !constant.IsValid(id, unique) {
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return unique, nil
+	}
+
+	found, err := s.categoryRepo.FindByFilter(ctx, bson.M{
+		"_id":    bson.M{"$in": unique},
+		"status": bson.M{"$ne": constant.CategoryStatusDeleted},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up categories: %w", err)
+	}
+	for _, category := range found {
+		if category.Status == constant.CategoryStatusArchived && !constant.IsValid(category.ID, current) {
+			return nil, fmt.Errorf("%w: %s", ErrCategoryArchived, category.ID.Hex())
+		}
+	}
+	if len(found) != len(unique) {
+		for _, id := range unique {
+			exists := false
+			for _, category := range found {
+				if category.ID == id {
+					exists = true
+					break
+				}
+			}
+			if !exists {
+				return nil, fmt.Errorf("%w: %s", ErrCategoryNotFound, id.Hex())
+			}
+		}
+	}
+	return unique, nil
+}
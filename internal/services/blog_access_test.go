@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+)
+
+func TestPostAccess(t *testing.T) {
+	users := map[string]*entity.User{
+		"anonymous": nil,
+		"author":    {ID: 1, Role: constant.RoleCJ},
+		"editor":    {ID: 2, Role: constant.RoleBasic},
+		"writer":    {ID: 3, Role: constant.RoleCJ},
+		"reader":    {ID: 4, Role: constant.RolePremium},
+		"admin":     {ID: 5, Role: constant.RoleAdmin},
+	}
+	post := func(status constant.BlogStatus) *entity.BlogPost {
+		return &entity.BlogPost{AuthorID: 1, EditorIDs: []uint64{2}, Status: status}
+	}
+
+	tests := []struct {
+		user                string
+		status              constant.BlogStatus
+		owner, edit, seeing bool
+	}{
+		{"anonymous", constant.BlogStatusPublished, false, false, true},
+		{"anonymous", constant.BlogStatusActive, false, false, true},
+		{"anonymous", constant.BlogStatusDraft, false, false, false},
+		{"author", constant.BlogStatusDraft, true, true, true},
+		{"author", constant.BlogStatusScheduled, true, true, true},
+		{"author", constant.BlogStatusDeleted, true, true, false},
+		{"editor", constant.BlogStatusPublished, false, true, true},
+		{"editor", constant.BlogStatusInReview, false, true, true},
+		{"writer", constant.BlogStatusPublished, false, false, true},
+		{"writer", constant.BlogStatusDraft, false, false, false},
+		{"reader", constant.BlogStatusArchived, false, false, false},
+		{"admin", constant.BlogStatusDraft, true, true, true},
+		{"admin", constant.BlogStatusDeleted, true, true, false},
+	}
+	for _, tc := range tests {
+		user, p := users[tc.user], post(tc.status)
+		if got := isPostOwner(user, p); got != tc.owner {
+			t.Errorf("isPostOwner(%s, %s post) = %v, want %v", tc.user, tc.status, got, tc.owner)
+		}
+		if got := canEditPost(user, p); got != tc.edit {
+			t.Errorf("canEditPost(%s, %s post) = %v, want %v", tc.user, tc.status, got, tc.edit)
+		}
+		if got := canSeePost(user, p); got != tc.seeing {
+			t.Errorf("canSeePost(%s, %s post) = %v, want %v", tc.user, tc.status, got, tc.seeing)
+		}
+	}
+}
+
+func TestPostWithoutAuthorHasNoOwner(t *testing.T) {
+	// Posts from before ownership was tracked have AuthorID 0, which must not
+	// match a user that somehow has ID 0.
+	p := &entity.BlogPost{Status: constant.BlogStatusDraft}
+	if isPostOwner(&entity.User{ID: 0, Role: constant.RoleCJ}, p) {
+		t.Error("a user with ID 0 owns an authorless post")
+	}
+	if !isPostOwner(&entity.User{ID: 9, Role: constant.RoleAdmin}, p) {
+		t.Error("an admin does not own an authorless post")
+	}
+}
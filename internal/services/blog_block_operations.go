@@ -0,0 +1,166 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/domain/request"
+	"github.com/gin-gonic/gin"
+)
+
+// applyBlockOperations validates each operation against the post's current
+// blocks and applies them in order to a copy. Nothing is written unless every
+// operation succeeds. Block orders are renumbered to match their final position.
+func (s *blogPostService) applyBlockOperations(c *gin.Context, current []entity.Block, ops []request.BlockOperationRequest) ([]entity.Block, error) {
+	blocks := make([]entity.Block, len(current))
+	copy(blocks, current)
+
+	for i, op := range ops {
+		var err error
+		switch op.Op {
+		case request.BlockOpInsert:
+			blocks, err = s.insertBlock(c, blocks, i, op)
+		case request.BlockOpMove:
+			blocks, err = moveBlock(blocks, op)
+		case request.BlockOpReplace:
+			blocks, err = s.replaceBlock(c, blocks, i, op)
+		case request.BlockOpDelete:
+			blocks, err = deleteBlock(blocks, op)
+		default:
+			err = fmt.Errorf("unsupported op %q", op.Op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: operation %d: %v", ErrInvalidOperation, i, err)
+		}
+	}
+
+	for i := range blocks {
+		blocks[i].Order = i + 1
+	}
+	return blocks, nil
+}
+
+func (s *blogPostService) insertBlock(c *gin.Context, blocks []entity.Block, opIndex int, op request.BlockOperationRequest) ([]entity.Block, error) {
+	if op.Block == nil {
+		return nil, fmt.Errorf("insert requires a block")
+	}
+
+	maxID := 0
+	for _, b := range blocks {
+		if b.ID > maxID {
+			maxID = b.ID
+		}
+	}
+	block, err := op.Block.ToEntity(maxID + 1)
+	if err != nil {
+		return nil, err
+	}
+	if blockIndex(blocks, block.ID) >= 0 {
+		return nil, fmt.Errorf("block %d already exists", block.ID)
+	}
+
+	position := len(blocks)
+	if op.Position != nil {
+		position = *op.Position
+	}
+	if position < 0 || position > len(blocks) {
+		return nil, fmt.Errorf("position %d out of range", position)
+	}
+
+	if err := s.uploadOperationMedia(c, &block, opIndex); err != nil {
+		return nil, err
+	}
+
+	blocks = append(blocks, entity.Block{})
+	copy(blocks[position+1:], blocks[position:])
+	blocks[position] = block
+	return blocks, nil
+}
+
+func moveBlock(blocks []entity.Block, op request.BlockOperationRequest) ([]entity.Block, error) {
+	from := blockIndex(blocks, op.BlockID)
+	if from < 0 {
+		return nil, fmt.Errorf("block %d not found", op.BlockID)
+	}
+	if op.Position == nil || *op.Position < 0 || *op.Position >= len(blocks) {
+		return nil, fmt.Errorf("move requires a position between 0 and %d", len(blocks)-1)
+	}
+
+	block := blocks[from]
+	blocks = append(blocks[:from], blocks[from+1:]...)
+	to := *op.Position
+	blocks = append(blocks, entity.Block{})
+	copy(blocks[to+1:], blocks[to:])
+	blocks[to] = block
+	return blocks, nil
+}
+
+func (s *blogPostService) replaceBlock(c *gin.Context, blocks []entity.Block, opIndex int, op request.BlockOperationRequest) ([]entity.Block, error) {
+	idx := blockIndex(blocks, op.BlockID)
+	if idx < 0 {
+		return nil, fmt.Errorf("block %d not found", op.BlockID)
+	}
+	if op.Block == nil {
+		return nil, fmt.Errorf("replace requires a block")
+	}
+
+	block, err := op.Block.ToEntity(op.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	block.ID = op.BlockID
+
+	if err := s.uploadOperationMedia(c, &block, opIndex); err != nil {
+		return nil, err
+	}
+
+	blocks[idx] = block
+	return blocks, nil
+}
+
+func deleteBlock(blocks []entity.Block, op request.BlockOperationRequest) ([]entity.Block, error) {
+	idx := blockIndex(blocks, op.BlockID)
+	if idx < 0 {
+		return nil, fmt.Errorf("block %d not found", op.BlockID)
+	}
+	return append(blocks[:idx], blocks[idx+1:]...), nil
+}
+
+func blockIndex(blocks []entity.Block, id int) int {
+	for i, b := range blocks {
+		if b.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// uploadOperationMedia pushes the file sent for an operation to S3, if any, and
+// points the block at it. Without a file the block keeps the filename it was given.
+func (s *blogPostService) uploadOperationMedia(c *gin.Context, block *entity.Block, opIndex int) error {
+	raw, ok := c.Get(fmt.Sprintf("operation_%d_fileBytes", opIndex))
+	if !ok {
+		return nil
+	}
+	fileBytes, ok := raw.([]byte)
+	if !ok {
+		return fmt.Errorf("invalid file data format")
+	}
+
+	switch block.Type {
+	case entity.BlockTypeImage:
+		s3Key, err := s.s3Uploader.UploadFile(constant.S3FolderImage, block.Image.Filename, "image/png", "0", fileBytes)
+		if err != nil {
+			return fmt.Errorf("upload img: %w", err)
+		}
+		block.Image.Filename = s3Key
+	case entity.BlockTypeVideo:
+		s3Key, err := s.s3Uploader.UploadFile(constant.S3FolderVideo, block.Video.Filename, "video/mp4", "0", fileBytes)
+		if err != nil {
+			return fmt.Errorf("upload vid: %w", err)
+		}
+		block.Video.Filename = s3Key
+	}
+	return nil
+}
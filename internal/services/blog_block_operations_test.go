@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/domain/request"
+	"github.com/gin-gonic/gin"
+)
+
+func TestApplyBlockOperations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	heading := func(text string) *request.CreateBlockRequest {
+		level := 2
+		return &request.CreateBlockRequest{Type: constant.MediaTypeHeading, HeadingLevel: &level, Text: &text}
+	}
+	at := func(i int) *int { return &i }
+	current := func() []entity.Block {
+		var blocks []entity.Block
+		for i, text := range []string{"a", "b", "c"} {
+			blocks = append(blocks, entity.Block{ID: i + 1, Order: i + 1, Type: entity.BlockTypeHeading, Heading: &entity.HeadingBlock{Level: 2, Text: text}})
+		}
+		return blocks
+	}
+
+	tests := []struct {
+		name    string
+		ops     []request.BlockOperationRequest
+		want    []string // "id:text" in final order
+		wantErr bool
+	}{
+		{"no operations", nil, []string{"1:a", "2:b", "3:c"}, false},
+		{"insert appends by default", []request.BlockOperationRequest{
+			{Op: request.BlockOpInsert, Block: heading("d")},
+		}, []string{"1:a", "2:b", "3:c", "4:d"}, false},
+		{"insert at the front", []request.BlockOperationRequest{
+			{Op: request.BlockOpInsert, Position: at(0), Block: heading("d")},
+		}, []string{"4:d", "1:a", "2:b", "3:c"}, false},
+		{"insert past the end", []request.BlockOperationRequest{
+			{Op: request.BlockOpInsert, Position: at(4), Block: heading("d")},
+		}, nil, true},
+		{"insert with a taken id", []request.BlockOperationRequest{
+			{Op: request.BlockOpInsert, Block: &request.CreateBlockRequest{ID: 2, Type: constant.MediaTypeImage}},
+		}, nil, true},
+		{"insert without a block", []request.BlockOperationRequest{{Op: request.BlockOpInsert}}, nil, true},
+		{"move down", []request.BlockOperationRequest{
+			{Op: request.BlockOpMove, BlockID: 1, Position: at(2)},
+		}, []string{"2:b", "3:c", "1:a"}, false},
+		{"move up", []request.BlockOperationRequest{
+			{Op: request.BlockOpMove, BlockID: 3, Position: at(0)},
+		}, []string{"3:c", "1:a", "2:b"}, false},
+		{"move without a position", []request.BlockOperationRequest{{Op: request.BlockOpMove, BlockID: 1}}, nil, true},
+		{"move out of range", []request.BlockOperationRequest{
+			{Op: request.BlockOpMove, BlockID: 1, Position: at(3)},
+		}, nil, true},
+		{"replace keeps id and position", []request.BlockOperationRequest{
+			{Op: request.BlockOpReplace, BlockID: 2, Block: heading("B")},
+		}, []string{"1:a", "2:B", "3:c"}, false},
+		{"replace a missing block", []request.BlockOperationRequest{
+			{Op: request.BlockOpReplace, BlockID: 9, Block: heading("x")},
+		}, nil, true},
+		{"delete", []request.BlockOperationRequest{
+			{Op: request.BlockOpDelete, BlockID: 2},
+		}, []string{"1:a", "3:c"}, false},
+		{"delete a missing block", []request.BlockOperationRequest{{Op: request.BlockOpDelete, BlockID: 9}}, nil, true},
+		{"operations apply in order", []request.BlockOperationRequest{
+			{Op: request.BlockOpDelete, BlockID: 3},
+			{Op: request.BlockOpInsert, Position: at(1), Block: heading("d")},
+			{Op: request.BlockOpMove, BlockID: 1, Position: at(2)},
+		}, []string{"3:d", "2:b", "1:a"}, false},
+		{"one bad operation rejects all", []request.BlockOperationRequest{
+			{Op: request.BlockOpDelete, BlockID: 1},
+			{Op: request.BlockOpDelete, BlockID: 1},
+		}, nil, true},
+		{"unknown op", []request.BlockOperationRequest{{Op: "split", BlockID: 1}}, nil, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			s := &blogPostService{}
+			before := current()
+			blocks := current()
+
+			got, err := s.applyBlockOperations(c, blocks, tc.ops)
+			if !reflect.DeepEqual(blocks, before) {
+				t.Errorf("current blocks were modified: %v", blocks)
+			}
+			if tc.wantErr {
+				if !errors.Is(err, ErrInvalidOperation) {
+					t.Errorf("err = %v, want ErrInvalidOperation", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for i, b := range got {
+				ids = append(ids, fmt.Sprintf("%d:%s", b.ID, b.Heading.Text))
+				if b.Order != i+1 {
+					t.Errorf("block %d has order %d at position %d", b.ID, b.Order, i)
+				}
+			}
+			if !reflect.DeepEqual(ids, tc.want) {
+				t.Errorf("blocks = %v, want %v", ids, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/domain/response"
+	"github.com/capigiba/capiary/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recordRevision appends an immutable snapshot of the post's current content,
+// attributed to the authenticated user.
+func (s *blogPostService) recordRevision(c *gin.Context, post entity.BlogPost, restoredFrom *primitive.ObjectID) error {
+	revision := entity.BlogRevision{
+		ID:           primitive.NewObjectID(),
+		PostID:       post.ID,
+		Title:        post.Title,
+		Blocks:       post.Blocks,
+		RestoredFrom: restoredFrom,
+		CreatedAt:    time.Now(),
+	}
+	if user := middleware.CurrentUser(c); user != nil {
+		revision.AuthorID = user.ID
+	}
+
+	if _, err := s.revisionRepo.Add(c.Request.Context(), revision); err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	return nil
+}
+
+// recordRevisionOrRevert records the revision for an update that has just
+// taken post from previous to its current content. Revisions are append-only,
+// so they cannot be written ahead of an update that may still conflict;
+// instead, if recording fails, the fields the update set are put back the way
+// they were, guarded by the new version, so the post never shows content that
+// its history lacks.
+func (s *blogPostService) recordRevisionOrRevert(c *gin.Context, post, previous entity.BlogPost, fields bson.M, restoredFrom *primitive.ObjectID) error {
+	err := s.recordRevision(c, post, restoredFrom)
+	if err == nil {
+		return nil
+	}
+
+	old := bson.M{
+		"title":      previous.Title,
+		"blocks":     previous.Blocks,
+		"categories": previous.Categories,
+		"slug":       previous.Slug,
+		"updated_at": previous.UpdatedAt,
+		"version":    previous.Version,
+	}
+	revert := bson.M{}
+	for field := range fields {
+		revert[field] = old[field]
+	}
+	matched, revertErr := s.repo.UpdateFieldsByQuery(c.Request.Context(), versionFilter(post.ID, post.Version), revert)
+	if revertErr == nil && matched == 0 {
+		revertErr = errors.New("post was modified concurrently")
+	}
+	if revertErr != nil {
+		return fmt.Errorf("%w; the update was kept because reverting it failed: %v", err, revertErr)
+	}
+	return err
+}
+
+// ListRevisions returns every revision of a post, newest first.
+func (s *blogPostService) ListRevisions(c *gin.Context, postID string) ([]entity.BlogRevision, error) {
+	ctx := c.Request.Context()
+	post, err := s.findPostByHex(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeRead(c, post); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.FindByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// DiffRevisions compares two revisions of the same post block by block.
+func (s *blogPostService) DiffRevisions(c *gin.Context, postID, fromID, toID string) (*response.RevisionDiff, error) {
+	ctx := c.Request.Context()
+	post, err := s.findPostByHex(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeRead(c, post); err != nil {
+		return nil, err
+	}
+
+	from, err := s.findRevisionByHex(ctx, post.ID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findRevisionByHex(ctx, post.ID, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &response.RevisionDiff{
+		FromRevision: from.ID.Hex(),
+		ToRevision:   to.ID.Hex(),
+		TitleChanged: from.Title != to.Title,
+		Blocks:       diffBlocks(from.Blocks, to.Blocks),
+	}
+	if diff.TitleChanged {
+		diff.FromTitle = from.Title
+		diff.ToTitle = to.Title
+	}
+	return diff, nil
+}
+
+// RestoreRevision makes an older revision's content current again. The restore
+// itself is recorded as a new revision, so history is never rewritten.
+func (s *blogPostService) RestoreRevision(c *gin.Context, postID, revisionID string) (*entity.BlogPost, error) {
+	ctx := c.Request.Context()
+	post, err := s.findPostByHex(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeEdit(c, post); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.findRevisionByHex(ctx, post.ID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *post
+	now := time.Now()
+	setDoc := bson.M{
+		"title":      revision.Title,
+		"blocks":     revision.Blocks,
+		"updated_at": now,
+		"version":    post.Version + 1,
+	}
+	matched, err := s.repo.UpdateFieldsByQuery(ctx, versionFilter(post.ID, post.Version), setDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore revision: %w", err)
+	}
+	if matched == 0 {
+		return nil, fmt.Errorf("%w: post was modified concurrently", ErrVersionConflict)
+	}
+
+	post.Title = revision.Title
+	post.Blocks = revision.Blocks
+	post.UpdatedAt = now
+	post.Version++
+	if err := s.recordRevisionOrRevert(c, *post, previous, setDoc, &revision.ID); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+func (s *blogPostService) findPostByHex(ctx context.Context, id string) (*entity.BlogPost, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert id to ObjectID: %w", err)
+	}
+
+	post, err := s.repo.FindByID(ctx, oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find post: %w", err)
+	}
+	if post == nil || post.Status == constant.BlogStatusDeleted {
+		return nil, ErrPostNotFound
+	}
+	return post, nil
+}
+
+func (s *blogPostService) findRevisionByHex(ctx context.Context, postID primitive.ObjectID, id string) (*entity.BlogRevision, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert revision id to ObjectID: %w", err)
+	}
+
+	revision, err := s.revisionRepo.FindByID(ctx, postID, oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find revision: %w", err)
+	}
+	if revision == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, nil
+}
+
+// diffBlocks matches blocks by ID. A block whose only change is its Order is
+// reported as moved; any other change is reported as modified.
+func diffBlocks(from, to []entity.Block) []response.BlockChange {
+	fromByID := make(map[int]entity.Block, len(from))
+	for _, b := range from {
+		fromByID[b.ID] = b
+	}
+	toByID := make(map[int]entity.Block, len(to))
+	for _, b := range to {
+		toByID[b.ID] = b
+	}
+
+	changes := []response.BlockChange{}
+	for id, old := range fromByID {
+		old := old
+		updated, ok := toByID[id]
+		if !ok {
+			changes = append(changes, response.BlockChange{BlockID: id, Type: response.BlockChangeRemoved, From: &old})
+			continue
+		}
+		if reflect.DeepEqual(old, updated) {
+			continue
+		}
+
+		changeType := response.BlockChangeModified
+		reordered := updated
+		reordered.Order = old.Order
+		if reflect.DeepEqual(old, reordered) {
+			changeType = response.BlockChangeMoved
+		}
+		changes = append(changes, response.BlockChange{BlockID: id, Type: changeType, From: &old, To: &updated})
+	}
+	for id, added := range toByID {
+		added := added
+		if _, ok := fromByID[id]; !ok {
+			changes = append(changes, response.BlockChange{BlockID: id, Type: response.BlockChangeAdded, To: &added})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].BlockID < changes[j].BlockID
+	})
+	return changes
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/domain/response"
+)
+
+func TestDiffBlocks(t *testing.T) {
+	heading := func(id, order int, text string) entity.Block {
+		return entity.Block{ID: id, Order: order, Type: entity.BlockTypeHeading, Heading: &entity.HeadingBlock{Level: 2, Text: text}}
+	}
+	type change struct {
+		id  int
+		typ response.BlockChangeType
+	}
+
+	tests := []struct {
+		name     string
+		from, to []entity.Block
+		want     []change
+	}{
+		{"unchanged", []entity.Block{heading(1, 0, "a")}, []entity.Block{heading(1, 0, "a")}, nil},
+		{"both empty", nil, nil, nil},
+		{"added", nil, []entity.Block{heading(1, 0, "a")}, []change{{1, response.BlockChangeAdded}}},
+		{"removed", []entity.Block{heading(1, 0, "a")}, nil, []change{{1, response.BlockChangeRemoved}}},
+		{"modified", []entity.Block{heading(1, 0, "a")}, []entity.Block{heading(1, 0, "b")}, []change{{1, response.BlockChangeModified}}},
+		{"moved", []entity.Block{heading(1, 0, "a"), heading(2, 1, "b")}, []entity.Block{heading(2, 0, "b"), heading(1, 1, "a")}, []change{
+			{1, response.BlockChangeMoved},
+			{2, response.BlockChangeMoved},
+		}},
+		{"moved and edited is modified", []entity.Block{heading(1, 0, "a")}, []entity.Block{heading(1, 3, "b")}, []change{{1, response.BlockChangeModified}}},
+		{"ordered by block id", []entity.Block{heading(3, 0, "c"), heading(2, 1, "b")}, []entity.Block{heading(1, 0, "a"), heading(2, 1, "B")}, []change{
+			{1, response.BlockChangeAdded},
+			{2, response.BlockChangeModified},
+			{3, response.BlockChangeRemoved},
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := diffBlocks(tc.from, tc.to)
+			if changes == nil {
+				t.Fatal("diffBlocks returned nil, want an empty list")
+			}
+			var got []change
+			for _, c := range changes {
+				got = append(got, change{c.BlockID, c.Type})
+				if (c.Type == response.BlockChangeAdded) != (c.From == nil) || (c.Type == response.BlockChangeRemoved) != (c.To == nil) {
+					t.Errorf("block %d %s has from %v and to %v", c.BlockID, c.Type, c.From, c.To)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("changes = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
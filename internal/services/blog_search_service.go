@@ -0,0 +1,49 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/capigiba/capiary/internal/domain/response"
+	"github.com/capigiba/capiary/internal/infra/search"
+	"github.com/gin-gonic/gin"
+)
+
+// SearchPosts runs a full-text search over post titles, headings and
+// paragraphs, best match first. Only published posts are searched, so drafts
+// never leak through snippets, and posts in categories the current user may
+// not read are left out.
+func (s *blogPostService) SearchPosts(c *gin.Context, text string, page, pageSize int) (*response.PostSearchResult, error) {
+	text = strings.TrimSpace(text)
+	if len(search.Terms(text)) == 0 {
+		return nil, ErrEmptySearchQuery
+	}
+
+	restricted, err := s.restrictedCategoryIDs(c)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.searcher.SearchPosts(c.Request.Context(), search.Query{
+		Text:              text,
+		Statuses:          readableStatuses,
+		ExcludeCategories: restricted,
+		Skip:              int64((page - 1) * pageSize),
+		Limit:             int64(pageSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+
+	hits := make([]response.PostSearchHit, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		post := hit.Post
+		s.attachMediaLinks(&post)
+		hits = append(hits, response.PostSearchHit{
+			Post:     post,
+			Score:    hit.Score,
+			Snippets: search.Snippets(hit.Post, text),
+		})
+	}
+	return &response.PostSearchResult{Hits: hits, Total: result.Total}, nil
+}
@@ -7,9 +7,14 @@ import (
 
 	"github.com/capigiba/capiary/internal/domain/constant"
 	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/domain/request"
+	"github.com/capigiba/capiary/internal/domain/response"
 	"github.com/capigiba/capiary/internal/infra/db/query"
+	"github.com/capigiba/capiary/internal/infra/search"
 	"github.com/capigiba/capiary/internal/infra/storage"
+	"github.com/capigiba/capiary/internal/middleware"
 	"github.com/capigiba/capiary/internal/repositories"
+	"github.com/capigiba/capiary/pkg/slug"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,21 +22,46 @@ import (
 
 type BlogPostService interface {
 	CreatePostWithFiles(c *gin.Context, post entity.BlogPost) (string, error)
-	FindPostsWithRawQuery(ctx context.Context, rawFilters, rawSorts []string, rawFields string, page, pageSize int) ([]entity.BlogPost, error)
-	UpdatePostByRawFilter(c *gin.Context, rawFilters []string, update entity.BlogPost) error
-	LoadAllPosts(ctx context.Context) ([]entity.BlogPost, error)
+	FindPostsWithRawQuery(c *gin.Context, rawFilters []string, rawWhere string, rawSorts []string, rawFields string, includeDescendants bool, page, pageSize int, cursor string, withTotal bool) ([]entity.BlogPost, query.PageInfo, error)
+	FindPostsByCategory(c *gin.Context, categoryID string, includeDescendants bool, page, pageSize int, cursor string, withTotal bool) ([]entity.BlogPost, query.PageInfo, error)
+	UpdatePostByRawFilter(c *gin.Context, rawFilters []string, update entity.BlogPost, expectedVersion *int64) (int64, error)
+	LoadAllPosts(c *gin.Context) ([]entity.BlogPost, error)
 	SoftDeletePostByRawFilter(ctx context.Context, rawFilters []string) error
+	TransitionPost(c *gin.Context, id string, to constant.BlogStatus, publishAt *time.Time) (*entity.BlogPost, error)
+	PublishDuePosts(ctx context.Context, now time.Time) (int, error)
+	FindPostByID(c *gin.Context, id string) (*entity.BlogPost, error)
+	FindPostBySlug(c *gin.Context, slug string) (*entity.BlogPost, error)
+	UpdatePostByID(c *gin.Context, id string, update entity.BlogPost, expectedVersion *int64) (int64, error)
+	PatchPost(c *gin.Context, id string, patch request.PatchBlogPostRequest, expectedVersion *int64) (*entity.BlogPost, error)
+	SoftDeletePostByID(c *gin.Context, id string) error
+	SetPostEditors(c *gin.Context, id string, editorIDs []uint64) (*entity.BlogPost, error)
+	ListRevisions(c *gin.Context, postID string) ([]entity.BlogRevision, error)
+	DiffRevisions(c *gin.Context, postID, fromID, toID string) (*response.RevisionDiff, error)
+	RestoreRevision(c *gin.Context, postID, revisionID string) (*entity.BlogPost, error)
+	SearchPosts(c *gin.Context, text string, page, pageSize int) (*response.PostSearchResult, error)
 }
 
 type blogPostService struct {
-	repo       repositories.BlogPostRepository
-	s3Uploader storage.S3UploaderInterface
+	repo         repositories.BlogPostRepository
+	revisionRepo repositories.BlogRevisionRepository
+	categoryRepo repositories.CategoryRepository
+	searcher     search.PostSearcher
+	s3Uploader   storage.S3UploaderInterface
 }
 
-func NewBlogPostService(repo repositories.BlogPostRepository, s3Uploader storage.S3UploaderInterface) BlogPostService {
+func NewBlogPostService(
+	repo repositories.BlogPostRepository,
+	revisionRepo repositories.BlogRevisionRepository,
+	categoryRepo repositories.CategoryRepository,
+	searcher search.PostSearcher,
+	s3Uploader storage.S3UploaderInterface,
+) BlogPostService {
 	return &blogPostService{
-		repo:       repo,
-		s3Uploader: s3Uploader,
+		repo:         repo,
+		revisionRepo: revisionRepo,
+		categoryRepo: categoryRepo,
+		searcher:     searcher,
+		s3Uploader:   s3Uploader,
 	}
 }
 
@@ -40,6 +70,12 @@ func (s *blogPostService) CreatePostWithFiles(c *gin.Context, post entity.BlogPo
 		return "", fmt.Errorf("title cannot be empty")
 	}
 
+	categories, err := s.validateCategories(c.Request.Context(), post.Categories, nil)
+	if err != nil {
+		return "", err
+	}
+	post.Categories = categories
+
 	// Loop over the blocks
 	for i := range post.Blocks {
 		switch post.Blocks[i].Type {
@@ -108,7 +144,16 @@ func (s *blogPostService) CreatePostWithFiles(c *gin.Context, post entity.BlogPo
 	post.CreatedAt = current_time
 	post.UpdatedAt = current_time
 
-	post.Status = constant.BlogStatusActive
+	// New posts always start as drafts; they only go live through TransitionPost.
+	post.Status = constant.BlogStatusDraft
+	post.PublishedAt = nil
+	post.Version = 1
+
+	postSlug, err := s.uniqueSlug(c.Request.Context(), post.Title, post.ID)
+	if err != nil {
+		return "", err
+	}
+	post.Slug = postSlug
 
 	// Finally store the post in the DB
 	insertedID, err := s.repo.Add(c.Request.Context(), post)
@@ -116,116 +161,339 @@ func (s *blogPostService) CreatePostWithFiles(c *gin.Context, post entity.BlogPo
 		return "", fmt.Errorf("failed to insert blog post: %w", err)
 	}
 
+	// A post must never exist without its first revision, so undo the insert
+	// if the revision cannot be written.
+	if err := s.recordRevision(c, post, nil); err != nil {
+		if deleteErr := s.repo.DeleteByID(c.Request.Context(), post.ID); deleteErr != nil {
+			return "", fmt.Errorf("%w; the post was kept because deleting it failed: %v", err, deleteErr)
+		}
+		return "", err
+	}
+
 	return insertedID, nil
 }
 
 // FindPostsWithRawQuery: parse the raw query params in the service, then build QueryOptions.
+// Posts the current user may not see, because they are unpublished or in
+// categories the user's role may not read, are left out.
 func (s *blogPostService) FindPostsWithRawQuery(
-	ctx context.Context,
-	rawFilters, rawSorts []string,
+	c *gin.Context,
+	rawFilters []string,
+	rawWhere string,
+	rawSorts []string,
 	rawFields string,
+	includeDescendants bool,
 	page, pageSize int,
-) ([]entity.BlogPost, error) {
-	parsedFilters, err := query.ParseFilters(rawFilters)
+	cursor string,
+	withTotal bool,
+) ([]entity.BlogPost, query.PageInfo, error) {
+	ctx := c.Request.Context()
+	parsedFilters, err := parseFilters(repositories.BlogPostSchema, rawFilters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse filters: %w", err)
+		return nil, query.PageInfo{}, err
 	}
 
-	// Convert "id" filter to "_id" if present:
-	for i, f := range parsedFilters {
-		if f.Field == "id" {
-			idStr, ok := f.Value.(string)
-			if !ok {
-				return nil, fmt.Errorf("invalid id filter value type")
-			}
-			oid, err := primitive.ObjectIDFromHex(idStr)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert id to ObjectID: %w", err)
+	where, err := parseWhere(repositories.BlogPostSchema, rawWhere)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+
+	// categories==X then also matches posts filed under any sub-category of X.
+	if includeDescendants {
+		leaves := where.Leaves()
+		for i := range parsedFilters {
+			leaves = append(leaves, &parsedFilters[i])
+		}
+		for _, f := range leaves {
+			if oid, ok := f.Value.(primitive.ObjectID); ok && f.Field == "categories" && f.Operator == query.OpEqual {
+				ids, err := descendantIDs(ctx, s.categoryRepo, oid)
+				if err != nil {
+					return nil, query.PageInfo{}, err
+				}
+				f.Operator = query.OpIn
+				f.Value = ids
 			}
-			parsedFilters[i].Field = "_id"
-			parsedFilters[i].Value = oid
 		}
 	}
 
-	// parsedFilters = append(parsedFilters, query.Filter{
-	// 	Field:    "status",
-	// 	Operator: query.OpEqual,
-	// 	Value:    constant.BlogStatusActive,
-	// })
+	// Readers only see published posts; authors and editors also see their own drafts.
+	where = query.And(where, visibilityCondition(middleware.CurrentUser(c)))
+
+	restricted, err := s.restrictedCategoryIDs(c)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	if len(restricted) > 0 {
+		parsedFilters = append(parsedFilters, query.Filter{
+			Field:    "categories",
+			Operator: query.OpNotIn,
+			Value:    restricted,
+		})
+	}
 
-	parsedSorts, err := query.ParseSorts(rawSorts)
+	parsedSorts, err := parseSorts(repositories.BlogPostSchema, rawSorts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse sorts: %w", err)
+		return nil, query.PageInfo{}, err
 	}
 	if len(parsedSorts) == 0 {
 		parsedSorts = []query.Sort{{Field: "created_at", Desc: true}}
 	}
 	parsedSorts = append(parsedSorts, query.Sort{Field: "_id", Desc: true})
 
-	parsedFields := query.ParseFields(rawFields)
+	parsedFields, err := parseFields(repositories.BlogPostSchema, rawFields)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	parsedFields = query.WithSortFields(parsedFields, parsedSorts)
 
 	opts := query.QueryOptions{
 		Filters: parsedFilters,
+		Where:   where,
 		Sorts:   parsedSorts,
 		Fields:  parsedFields,
 		Skip:    int64((page - 1) * pageSize),
 		Limit:   int64(pageSize),
 	}
+	// The total covers the whole listing, so count before the cursor narrows it.
+	var total *int64
+	if withTotal {
+		countFilter, _ := query.BuildMongoQuery(opts)
+		n, err := s.repo.CountByQuery(ctx, countFilter)
+		if err != nil {
+			return nil, query.PageInfo{}, fmt.Errorf("failed to count posts: %w", err)
+		}
+		total = &n
+	}
+
+	cur, err := applyCursor(&opts, cursor, query.NullsSmallest)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
 
 	posts, err := s.repo.FindByQuery(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find posts: %w", err)
+		return nil, query.PageInfo{}, fmt.Errorf("failed to find posts: %w", err)
+	}
+	posts, info, err := pageResult(posts, pageSize, cur, parsedSorts, page > 1, query.MongoSortValues)
+	if err != nil {
+		return nil, query.PageInfo{}, err
 	}
+	info.Total = total
 
 	for pIdx := range posts {
-		for bIdx := range posts[pIdx].Blocks {
-			block := &posts[pIdx].Blocks[bIdx]
-			switch block.Type {
-			case entity.BlockTypeImage:
-				if block.Image != nil && block.Image.Filename != "" {
-					link, presignErr := s.s3Uploader.GeneratePresignedURL(block.Image.Filename, 15*time.Minute)
-					if presignErr == nil {
-						block.Image.Link = &link
-					} else {
-						// Handle or log presignErr as needed
-					}
+		s.attachMediaLinks(&posts[pIdx])
+	}
+
+	return posts, info, nil
+}
+
+// FindPostsByCategory lists the posts of one category the current user may see, newest first.
+// With includeDescendants, posts of its sub-categories are listed as well.
+// Paging works as in FindPostsWithRawQuery.
+func (s *blogPostService) FindPostsByCategory(c *gin.Context, categoryID string, includeDescendants bool, page, pageSize int, cursor string, withTotal bool) ([]entity.BlogPost, query.PageInfo, error) {
+	ctx := c.Request.Context()
+	oid, err := primitive.ObjectIDFromHex(categoryID)
+	if err != nil {
+		return nil, query.PageInfo{}, fmt.Errorf("failed to convert category id to ObjectID: %w", err)
+	}
+
+	category, err := s.categoryRepo.FindByID(ctx, oid)
+	if err != nil {
+		return nil, query.PageInfo{}, fmt.Errorf("failed to find category: %w", err)
+	}
+	if category == nil || category.Status == constant.CategoryStatusDeleted {
+		return nil, query.PageInfo{}, ErrCategoryNotFound
+	}
+
+	restricted, err := s.restrictedCategoryIDs(c)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	if constant.IsValid(oid, restricted) {
+		return nil, query.PageInfo{}, ErrReadForbidden
+	}
+
+	filters := []query.Filter{
+		{Field: "categories", Operator: query.OpEqual, Value: oid},
+	}
+	if includeDescendants {
+		ids, err := descendantIDs(ctx, s.categoryRepo, oid)
+		if err != nil {
+			return nil, query.PageInfo{}, err
+		}
+		filters[0] = query.Filter{Field: "categories", Operator: query.OpIn, Value: ids}
+	}
+	if len(restricted) > 0 {
+		filters = append(filters, query.Filter{Field: "categories", Operator: query.OpNotIn, Value: restricted})
+	}
+
+	sorts := []query.Sort{{Field: "created_at", Desc: true}, {Field: "_id", Desc: true}}
+	opts := query.QueryOptions{
+		Filters: filters,
+		Where:   visibilityCondition(middleware.CurrentUser(c)),
+		Sorts:   sorts,
+		Skip:    int64((page - 1) * pageSize),
+		Limit:   int64(pageSize),
+	}
+	var total *int64
+	if withTotal {
+		countFilter, _ := query.BuildMongoQuery(opts)
+		n, err := s.repo.CountByQuery(ctx, countFilter)
+		if err != nil {
+			return nil, query.PageInfo{}, fmt.Errorf("failed to count posts: %w", err)
+		}
+		total = &n
+	}
+
+	cur, err := applyCursor(&opts, cursor, query.NullsSmallest)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+
+	posts, err := s.repo.FindByQuery(ctx, opts)
+	if err != nil {
+		return nil, query.PageInfo{}, fmt.Errorf("failed to find posts: %w", err)
+	}
+	posts, info, err := pageResult(posts, pageSize, cur, sorts, page > 1, query.MongoSortValues)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	info.Total = total
+
+	for pIdx := range posts {
+		s.attachMediaLinks(&posts[pIdx])
+	}
+	return posts, info, nil
+}
+
+// attachMediaLinks fills in short-lived presigned URLs for the post's image and video blocks.
+func (s *blogPostService) attachMediaLinks(post *entity.BlogPost) {
+	for bIdx := range post.Blocks {
+		block := &post.Blocks[bIdx]
+		switch block.Type {
+		case entity.BlockTypeImage:
+			if block.Image != nil && block.Image.Filename != "" {
+				link, presignErr := s.s3Uploader.GeneratePresignedURL(block.Image.Filename, 15*time.Minute)
+				if presignErr == nil {
+					block.Image.Link = &link
+				} else {
+					// Handle or log presignErr as needed
 				}
-			case entity.BlockTypeVideo:
-				if block.Video != nil && block.Video.Filename != "" {
-					link, presignErr := s.s3Uploader.GeneratePresignedURL(block.Video.Filename, 15*time.Minute)
-					if presignErr == nil {
-						block.Video.Link = &link
-					} else {
-						// Handle or log presignErr as needed
-					}
+			}
+		case entity.BlockTypeVideo:
+			if block.Video != nil && block.Video.Filename != "" {
+				link, presignErr := s.s3Uploader.GeneratePresignedURL(block.Video.Filename, 15*time.Minute)
+				if presignErr == nil {
+					block.Video.Link = &link
+				} else {
+					// Handle or log presignErr as needed
 				}
 			}
 		}
 	}
+}
 
-	return posts, nil
+// FindPostByID returns a single non-deleted post with media links attached.
+func (s *blogPostService) FindPostByID(c *gin.Context, id string) (*entity.BlogPost, error) {
+	post, err := s.findPostByHex(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeRead(c, post); err != nil {
+		return nil, err
+	}
+	s.attachMediaLinks(post)
+	return post, nil
 }
 
-// For update, we parse raw filters and build a bson.M filter. Then we call the repo method.
-func (s *blogPostService) UpdatePostByRawFilter(c *gin.Context, rawFilters []string, update entity.BlogPost) error {
-	parsed, err := query.ParseFilters(rawFilters)
+// FindPostBySlug returns a single non-deleted post with media links attached.
+func (s *blogPostService) FindPostBySlug(c *gin.Context, postSlug string) (*entity.BlogPost, error) {
+	post, err := s.repo.FindOneByQuery(c.Request.Context(), bson.M{"slug": postSlug})
 	if err != nil {
-		return fmt.Errorf("parse filter: %w", err)
+		return nil, fmt.Errorf("failed to find post: %w", err)
+	}
+	if post == nil || post.Status == constant.BlogStatusDeleted {
+		return nil, ErrPostNotFound
 	}
+	if err := s.authorizeRead(c, post); err != nil {
+		return nil, err
+	}
+	s.attachMediaLinks(post)
+	return post, nil
+}
 
-	for i, f := range parsed {
-		if f.Field == "id" {
-			if hex, ok := f.Value.(string); ok {
-				if oid, err := primitive.ObjectIDFromHex(hex); err == nil {
-					parsed[i].Field = "_id"
-					parsed[i].Value = oid
-				}
-			}
+// uniqueSlug derives a slug from the title and appends a numeric suffix until
+// it no longer collides with another post's slug.
+func (s *blogPostService) uniqueSlug(ctx context.Context, title string, id primitive.ObjectID) (string, error) {
+	base := slug.Make(title)
+	if base == "" {
+		base = "post"
+	}
+
+	candidate := base
+	for n := 2; ; n++ {
+		existing, err := s.repo.FindOneByQuery(ctx, bson.M{"slug": candidate, "_id": bson.M{"$ne": id}})
+		if err != nil {
+			return "", fmt.Errorf("failed to check slug uniqueness: %w", err)
+		}
+		if existing == nil {
+			return candidate, nil
 		}
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// For update, we parse raw filters and build a bson.M filter. Then we call the repo method.
+// When expectedVersion is set (from If-Match) the update only succeeds if the post is
+// still at that version; either way a concurrent write in between yields ErrVersionConflict.
+// The filter must match exactly one post, otherwise ErrAmbiguousFilter is returned.
+// It returns the post's new version.
+func (s *blogPostService) UpdatePostByRawFilter(c *gin.Context, rawFilters []string, update entity.BlogPost, expectedVersion *int64) (int64, error) {
+	parsed, err := parseFilters(repositories.BlogPostSchema, rawFilters)
+	if err != nil {
+		return 0, err
 	}
 
 	filterDoc, _ := query.BuildMongoQuery(query.QueryOptions{Filters: parsed})
 
+	matched, err := s.repo.CountByQuery(c.Request.Context(), filterDoc)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count posts: %w", err)
+	}
+	if matched > 1 {
+		return 0, fmt.Errorf("%w: %d posts match", ErrAmbiguousFilter, matched)
+	}
+	current, err := s.repo.FindOneByQuery(c.Request.Context(), filterDoc)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find post: %w", err)
+	}
+	if current == nil {
+		return 0, ErrPostNotFound
+	}
+
+	return s.updatePost(c, current, update, expectedVersion)
+}
+
+// UpdatePostByID replaces the title and blocks of a single post, like UpdatePostByRawFilter.
+func (s *blogPostService) UpdatePostByID(c *gin.Context, id string, update entity.BlogPost, expectedVersion *int64) (int64, error) {
+	current, err := s.findPostByHex(c.Request.Context(), id)
+	if err != nil {
+		return 0, err
+	}
+	return s.updatePost(c, current, update, expectedVersion)
+}
+
+// updatePost uploads any new media, writes the new content guarded by the
+// post's version and records a revision.
+func (s *blogPostService) updatePost(c *gin.Context, current *entity.BlogPost, update entity.BlogPost, expectedVersion *int64) (int64, error) {
+	if err := authorizeEdit(c, current); err != nil {
+		return 0, err
+	}
+	if expectedVersion != nil && *expectedVersion != current.Version {
+		return 0, fmt.Errorf("%w: expected version %d, current version %d", ErrVersionConflict, *expectedVersion, current.Version)
+	}
+	previous := *current
+
 	// 1) iterate blocks – if a new fileBytes exists, push to S3 & overwrite filename
 	for i := range update.Blocks {
 		switch update.Blocks[i].Type {
@@ -242,7 +510,7 @@ func (s *blogPostService) UpdatePostByRawFilter(c *gin.Context, rawFilters []str
 					bytes,
 				)
 				if err != nil {
-					return fmt.Errorf("upload img %d: %w", i, err)
+					return 0, fmt.Errorf("upload img %d: %w", i, err)
 				}
 				update.Blocks[i].Image.Filename = s3Key
 			}
@@ -259,7 +527,7 @@ func (s *blogPostService) UpdatePostByRawFilter(c *gin.Context, rawFilters []str
 					bytes,
 				)
 				if err != nil {
-					return fmt.Errorf("upload vid %d: %w", i, err)
+					return 0, fmt.Errorf("upload vid %d: %w", i, err)
 				}
 				update.Blocks[i].Video.Filename = s3Key
 			}
@@ -273,25 +541,71 @@ func (s *blogPostService) UpdatePostByRawFilter(c *gin.Context, rawFilters []str
 		"title":      update.Title,
 		"blocks":     update.Blocks,
 		"updated_at": update.UpdatedAt,
+		"version":    current.Version + 1,
+	}
+	// Categories are only replaced when the request sent them.
+	if update.Categories != nil {
+		categories, err := s.validateCategories(c.Request.Context(), update.Categories, current.Categories)
+		if err != nil {
+			return 0, err
+		}
+		setDoc["categories"] = categories
+		current.Categories = categories
+	}
+	// Posts created before slugs existed get one on their first update.
+	if current.Slug == "" {
+		postSlug, err := s.uniqueSlug(c.Request.Context(), update.Title, current.ID)
+		if err != nil {
+			return 0, err
+		}
+		setDoc["slug"] = postSlug
 	}
 
-	return s.repo.UpdateFieldsByQuery(c.Request.Context(), filterDoc, setDoc)
+	matched, err := s.repo.UpdateFieldsByQuery(c.Request.Context(), versionFilter(current.ID, current.Version), setDoc)
+	if err != nil {
+		return 0, err
+	}
+	if matched == 0 {
+		return 0, fmt.Errorf("%w: post was modified concurrently", ErrVersionConflict)
+	}
+
+	current.Title = update.Title
+	current.Blocks = update.Blocks
+	current.Version++
+	if err := s.recordRevisionOrRevert(c, *current, previous, setDoc, nil); err != nil {
+		return 0, err
+	}
+	return current.Version, nil
 }
 
-func (s *blogPostService) LoadAllPosts(ctx context.Context) ([]entity.BlogPost, error) {
-	posts, err := s.repo.LoadAll(ctx)
+// LoadAllPosts returns every post the current user may see.
+func (s *blogPostService) LoadAllPosts(c *gin.Context) ([]entity.BlogPost, error) {
+	posts, err := s.repo.LoadAll(c.Request.Context())
 	if err != nil {
 		return nil, fmt.Errorf("failed to load all posts: %w", err)
 	}
-	return posts, nil
+
+	restricted, err := s.restrictedCategoryIDs(c)
+	if err != nil {
+		return nil, err
+	}
+
+	user := middleware.CurrentUser(c)
+	visible := make([]entity.BlogPost, 0, len(posts))
+	for i := range posts {
+		if canSeePost(user, &posts[i]) && !inRestrictedCategory(posts[i], restricted) {
+			visible = append(visible, posts[i])
+		}
+	}
+	return visible, nil
 }
 
 func (s *blogPostService) SoftDeletePostByRawFilter(
 	ctx context.Context, rawFilters []string) error {
 
-	parsedFilters, err := query.ParseFilters(rawFilters)
+	parsedFilters, err := parseFilters(repositories.BlogPostSchema, rawFilters)
 	if err != nil {
-		return fmt.Errorf("failed to parse filters: %w", err)
+		return err
 	}
 
 	filterDoc, _ := query.BuildMongoQuery(query.QueryOptions{
@@ -303,5 +617,240 @@ func (s *blogPostService) SoftDeletePostByRawFilter(
 		"updated_at": time.Now(),
 	}
 
-	return s.repo.UpdateFieldsByQuery(ctx, filterDoc, fields)
+	_, err = s.repo.UpdateFieldsByQuery(ctx, filterDoc, fields)
+	return err
+}
+
+// PatchPost updates only the fields present in the patch and applies its block
+// operations. The whole patch is written in one version-guarded update.
+func (s *blogPostService) PatchPost(c *gin.Context, id string, patch request.PatchBlogPostRequest, expectedVersion *int64) (*entity.BlogPost, error) {
+	ctx := c.Request.Context()
+	post, err := s.findPostByHex(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeEdit(c, post); err != nil {
+		return nil, err
+	}
+	if expectedVersion != nil && *expectedVersion != post.Version {
+		return nil, fmt.Errorf("%w: expected version %d, current version %d", ErrVersionConflict, *expectedVersion, post.Version)
+	}
+	previous := *post
+
+	now := time.Now()
+	setDoc := bson.M{
+		"updated_at": now,
+		"version":    post.Version + 1,
+	}
+	if patch.Title != nil {
+		if *patch.Title == "" {
+			return nil, fmt.Errorf("title cannot be empty")
+		}
+		setDoc["title"] = *patch.Title
+		post.Title = *patch.Title
+	}
+	if patch.Categories != nil {
+		ids, err := request.ObjectIDsFromHex(*patch.Categories)
+		if err != nil {
+			return nil, err
+		}
+		categories, err := s.validateCategories(ctx, ids, post.Categories)
+		if err != nil {
+			return nil, err
+		}
+		setDoc["categories"] = categories
+		post.Categories = categories
+	}
+	if len(patch.Operations) > 0 {
+		blocks, err := s.applyBlockOperations(c, post.Blocks, patch.Operations)
+		if err != nil {
+			return nil, err
+		}
+		setDoc["blocks"] = blocks
+		post.Blocks = blocks
+	}
+
+	matched, err := s.repo.UpdateFieldsByQuery(ctx, versionFilter(post.ID, post.Version), setDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch post: %w", err)
+	}
+	if matched == 0 {
+		return nil, fmt.Errorf("%w: post was modified concurrently", ErrVersionConflict)
+	}
+
+	post.UpdatedAt = now
+	post.Version++
+	if patch.Title != nil || len(patch.Operations) > 0 {
+		if err := s.recordRevisionOrRevert(c, *post, previous, setDoc, nil); err != nil {
+			return nil, err
+		}
+	}
+	return post, nil
+}
+
+// SoftDeletePostByID marks a single post as deleted. Only its author or an admin may do so.
+func (s *blogPostService) SoftDeletePostByID(c *gin.Context, id string) error {
+	ctx := c.Request.Context()
+	post, err := s.findPostByHex(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeOwner(c, post); err != nil {
+		return err
+	}
+
+	fields := bson.M{
+		"status":     constant.BlogStatusDeleted,
+		"updated_at": time.Now(),
+	}
+	matched, err := s.repo.UpdateFieldsByQuery(ctx, bson.M{"_id": post.ID}, fields)
+	if err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+	if matched == 0 {
+		return ErrPostNotFound
+	}
+	return nil
+}
+
+// SetPostEditors replaces the co-authors of a post. Only its author or an admin may do so.
+// The author is never listed as their own editor and duplicates are dropped.
+func (s *blogPostService) SetPostEditors(c *gin.Context, id string, editorIDs []uint64) (*entity.BlogPost, error) {
+	ctx := c.Request.Context()
+	post, err := s.findPostByHex(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOwner(c, post); err != nil {
+		return nil, err
+	}
+
+	editors := []uint64{}
+	for _, editorID := range editorIDs {
+		if editorID == 0 || editorID == post.AuthorID || constant.IsValid(editorID, editors) {
+			continue
+		}
+		editors = append(editors, editorID)
+	}
+
+	now := time.Now()
+	fields := bson.M{
+		"editor_ids": editors,
+		"updated_at": now,
+		"version":    post.Version + 1,
+	}
+	matched, err := s.repo.UpdateFieldsByQuery(ctx, versionFilter(post.ID, post.Version), fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update editors: %w", err)
+	}
+	if matched == 0 {
+		return nil, fmt.Errorf("%w: post was modified concurrently", ErrVersionConflict)
+	}
+
+	post.EditorIDs = editors
+	post.UpdatedAt = now
+	post.Version++
+	return post, nil
+}
+
+// TransitionPost moves a post through the editorial workflow, rejecting any
+// transition that constant.BlogStatusTransitions does not allow.
+// publishAt is only used, and then required, when scheduling a post.
+func (s *blogPostService) TransitionPost(c *gin.Context, id string, to constant.BlogStatus, publishAt *time.Time) (*entity.BlogPost, error) {
+	if !constant.IsValidBlogStatus(to) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidStatus, to)
+	}
+
+	ctx := c.Request.Context()
+	post, err := s.findPostByHex(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeEdit(c, post); err != nil {
+		return nil, err
+	}
+	if to == constant.BlogStatusScheduled || to == constant.BlogStatusPublished {
+		if err := authorizePermission(c, constant.PermissionPostPublish); err != nil {
+			return nil, err
+		}
+	}
+	if !constant.CanTransitionBlogStatus(post.Status, to) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, post.Status, to)
+	}
+
+	now := time.Now()
+	fields := bson.M{
+		"status":     to,
+		"updated_at": now,
+		"version":    post.Version + 1,
+	}
+	if to == constant.BlogStatusScheduled {
+		if publishAt == nil || !publishAt.After(now) {
+			return nil, ErrInvalidPublishAt
+		}
+		fields["publish_at"] = *publishAt
+		post.PublishAt = publishAt
+	}
+	if to == constant.BlogStatusPublished && post.PublishedAt == nil {
+		fields["published_at"] = now
+		post.PublishedAt = &now
+	}
+
+	// Filter on the current status too: the scheduler publishes without
+	// bumping the version, and must not be overwritten either.
+	filter := versionFilter(post.ID, post.Version)
+	filter["status"] = post.Status
+	matched, err := s.repo.UpdateFieldsByQuery(ctx, filter, fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transition post: %w", err)
+	}
+	if matched == 0 {
+		return nil, fmt.Errorf("%w: post was modified concurrently", ErrVersionConflict)
+	}
+
+	post.Status = to
+	post.UpdatedAt = now
+	post.Version++
+	return post, nil
+}
+
+// PublishDuePosts publishes every scheduled post whose publish_at is not after now.
+// Each post is claimed with a single findAndModify that also flips its status, so
+// several replicas can run this concurrently without publishing a post twice.
+func (s *blogPostService) PublishDuePosts(ctx context.Context, now time.Time) (int, error) {
+	filter := bson.M{
+		"status":     constant.BlogStatusScheduled,
+		"publish_at": bson.M{"$lte": now},
+	}
+	sort := bson.D{{Key: "publish_at", Value: 1}}
+
+	published := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return published, err
+		}
+
+		fields := bson.M{
+			"status":       constant.BlogStatusPublished,
+			"published_at": now,
+			"updated_at":   now,
+		}
+		post, err := s.repo.FindOneAndUpdateFields(ctx, filter, fields, sort)
+		if err != nil {
+			return published, fmt.Errorf("failed to publish scheduled post: %w", err)
+		}
+		if post == nil {
+			return published, nil
+		}
+		published++
+	}
+}
+
+// versionFilter matches the post only while it is still at the given version.
+// Posts written before versioning have no version field and decode as 0.
+func versionFilter(id primitive.ObjectID, version int64) bson.M {
+	if version == 0 {
+		return bson.M{"_id": id, "version": bson.M{"$in": bson.A{0, nil}}}
+	}
+	return bson.M{"_id": id, "version": version}
 }
@@ -2,10 +2,15 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/capigiba/capiary/internal/domain/constant"
 	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/domain/response"
+	"github.com/capigiba/capiary/internal/infra/db/mongodb"
 	"github.com/capigiba/capiary/internal/infra/db/query"
 	"github.com/capigiba/capiary/internal/repositories"
 	"github.com/gin-gonic/gin"
@@ -15,26 +20,50 @@ import (
 
 type CategoryService interface {
 	Create(c *gin.Context, category entity.Category) (string, error)
-	Find(ctx context.Context, rawFilters, rawSorts []string, rawFields string, page, pageSize int) ([]entity.Category, error)
-	UpdateByRawFilter(c *gin.Context, rawFilters []string, update entity.Category) error
+	Find(ctx context.Context, rawFilters []string, rawWhere string, rawSorts []string, rawFields string, page, pageSize int, cursor string, withTotal bool) ([]entity.Category, query.PageInfo, error)
+	UpdateByRawFilter(c *gin.Context, rawFilters []string, update entity.Category, parentID *string) error
 	LoadAll(ctx context.Context) ([]entity.Category, error)
+	Tree(ctx context.Context) ([]*response.CategoryNode, error)
+	Delete(ctx context.Context, id string, cascade bool) (int64, error)
+	Archive(ctx context.Context, id string) error
+	Unarchive(ctx context.Context, id string) error
+	Merge(ctx context.Context, sourceID, targetID string) (int64, error)
 }
 
 type categoryService struct {
-	repo repositories.CategoryRepository
+	repo     repositories.CategoryRepository
+	blogRepo repositories.BlogPostRepository
 }
 
-func NewCategoryService(repo repositories.CategoryRepository) CategoryService {
+func NewCategoryService(repo repositories.CategoryRepository, blogRepo repositories.BlogPostRepository) CategoryService {
 	return &categoryService{
-		repo: repo,
+		repo:     repo,
+		blogRepo: blogRepo,
 	}
 }
 
 func (s *categoryService) Create(c *gin.Context, category entity.Category) (string, error) {
+	category.Name = strings.TrimSpace(category.Name)
 	if category.Name == "" {
 		return "", fmt.Errorf("cannot update post with empty name")
 	}
+	if err := validateAccess(category.Access); err != nil {
+		return "", err
+	}
+	ancestors, err := s.ancestorsUnder(c.Request.Context(), category.ParentID)
+	if err != nil {
+		return "", err
+	}
+	category.Ancestors = ancestors
+	category.Status = constant.CategoryStatusActive
+	category.NormalizedName = entity.NormalizeCategoryName(category.Name)
+	now := time.Now()
+	category.CreatedAt = now
+	category.UpdatedAt = now
 	insertedID, err := s.repo.Add(c.Request.Context(), category)
+	if errors.Is(err, mongodb.ErrDuplicateKey) {
+		return "", &ConflictError{Resource: "category", Field: "name", Value: category.Name}
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to insert new category: %w", err)
 	}
@@ -44,95 +73,341 @@ func (s *categoryService) Create(c *gin.Context, category entity.Category) (stri
 
 func (s *categoryService) Find(
 	ctx context.Context,
-	rawFilters, rawSorts []string,
+	rawFilters []string,
+	rawWhere string,
+	rawSorts []string,
 	rawFields string,
 	page, pageSize int,
-) ([]entity.Category, error) {
-	parsedFilters, err := query.ParseFilters(rawFilters)
+	cursor string,
+	withTotal bool,
+) ([]entity.Category, query.PageInfo, error) {
+	parsedFilters, err := parseFilters(repositories.CategorySchema, rawFilters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse filters: %w", err)
+		return nil, query.PageInfo{}, err
 	}
+	parsedFilters = append(parsedFilters, notDeletedFilter)
 
-	// Convert "id" filter to "_id" if present:
-	for i, f := range parsedFilters {
-		if f.Field == "id" {
-			idStr, ok := f.Value.(string)
-			if !ok {
-				return nil, fmt.Errorf("invalid id filter value type")
-			}
-			oid, err := primitive.ObjectIDFromHex(idStr)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert id to ObjectID: %w", err)
-			}
-			parsedFilters[i].Field = "_id"
-			parsedFilters[i].Value = oid
-		}
+	where, err := parseWhere(repositories.CategorySchema, rawWhere)
+	if err != nil {
+		return nil, query.PageInfo{}, err
 	}
 
-	parsedSorts, err := query.ParseSorts(rawSorts)
+	parsedSorts, err := parseSorts(repositories.CategorySchema, rawSorts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse sorts: %w", err)
+		return nil, query.PageInfo{}, err
 	}
 	if len(parsedSorts) == 0 {
 		parsedSorts = []query.Sort{{Field: "created_at", Desc: true}}
 	}
 	parsedSorts = append(parsedSorts, query.Sort{Field: "_id", Desc: true})
 
-	parsedFields := query.ParseFields(rawFields)
+	parsedFields, err := parseFields(repositories.CategorySchema, rawFields)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	parsedFields = query.WithSortFields(parsedFields, parsedSorts)
 
 	opts := query.QueryOptions{
 		Filters: parsedFilters,
+		Where:   where,
 		Sorts:   parsedSorts,
 		Fields:  parsedFields,
 		Skip:    int64((page - 1) * pageSize),
 		Limit:   int64(pageSize),
 	}
+	// The total covers the whole listing, so count before the cursor narrows it.
+	var total *int64
+	if withTotal {
+		countFilter, _ := query.BuildMongoQuery(opts)
+		n, err := s.repo.CountByQuery(ctx, countFilter)
+		if err != nil {
+			return nil, query.PageInfo{}, fmt.Errorf("failed to count categories: %w", err)
+		}
+		total = &n
+	}
+
+	cur, err := applyCursor(&opts, cursor, query.NullsSmallest)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
 
 	categories, err := s.repo.FindByQuery(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find categories: %w", err)
+		return nil, query.PageInfo{}, fmt.Errorf("failed to find categories: %w", err)
+	}
+	categories, info, err := pageResult(categories, pageSize, cur, parsedSorts, page > 1, query.MongoSortValues)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	info.Total = total
+	if err := s.attachBreadcrumbs(ctx, categories); err != nil {
+		return nil, query.PageInfo{}, err
 	}
 
-	return categories, nil
+	return categories, info, nil
 }
 
-func (s *categoryService) UpdateByRawFilter(c *gin.Context, rawFilters []string, update entity.Category) error {
-	parsedFilters, err := query.ParseFilters(rawFilters)
+// UpdateByRawFilter updates the matching categories. A non-nil parentID moves
+// the category under that parent, or to the root when it is empty; since a move
+// rewrites a whole subtree, the filter must then match exactly one category.
+func (s *categoryService) UpdateByRawFilter(c *gin.Context, rawFilters []string, update entity.Category, parentID *string) error {
+	ctx := c.Request.Context()
+	parsedFilters, err := parseFilters(repositories.CategorySchema, rawFilters)
 	if err != nil {
-		return fmt.Errorf("failed to parse filters: %w", err)
-
-	}
-
-	for i, f := range parsedFilters {
-		if f.Field == "id" {
-			if hex, ok := f.Value.(string); ok {
-				if oid, err := primitive.ObjectIDFromHex(hex); err == nil {
-					parsedFilters[i].Field = "_id"
-					parsedFilters[i].Value = oid
-				}
-			}
-		}
+		return err
 	}
 
 	filterDoc, _ := query.BuildMongoQuery(query.QueryOptions{
-		Filters: parsedFilters})
+		Filters: append(parsedFilters, notDeletedFilter)})
 
+	update.Name = strings.TrimSpace(update.Name)
 	if update.Name == "" {
 		return fmt.Errorf("cannot update post with empty name")
 	}
 	update.UpdatedAt = time.Now()
 	setDoc := bson.M{
-		"name":        update.Name,
-		"description": update.Description,
-		"updated_at":  update.UpdatedAt,
+		"name":            update.Name,
+		"normalized_name": entity.NormalizeCategoryName(update.Name),
+		"description":     update.Description,
+		"updated_at":      update.UpdatedAt,
+	}
+	// A nil Access means "leave unchanged"; an empty one makes the category public.
+	if update.Access != nil {
+		if err := validateAccess(update.Access); err != nil {
+			return err
+		}
+		setDoc["access"] = update.Access
 	}
-	return s.repo.UpdateFieldsByQuery(c.Request.Context(), filterDoc, setDoc)
+
+	if parentID != nil {
+		var parent *primitive.ObjectID
+		if *parentID != "" {
+			oid, err := primitive.ObjectIDFromHex(*parentID)
+			if err != nil {
+				return fmt.Errorf("failed to convert parent_id to ObjectID: %w", err)
+			}
+			parent = &oid
+		}
+
+		matches, err := s.repo.FindByFilter(ctx, filterDoc)
+		if err != nil {
+			return fmt.Errorf("failed to find category: %w", err)
+		}
+		if len(matches) == 0 {
+			return ErrCategoryNotFound
+		}
+		if len(matches) > 1 {
+			return fmt.Errorf("parent_id can only be changed on a single category, filter matched %d", len(matches))
+		}
+		// The move is written before the rename, so a taken name must be
+		// caught here rather than by the unique index once the subtree has moved.
+		count, err := s.repo.CountByQuery(ctx, bson.M{
+			"normalized_name": setDoc["normalized_name"],
+			"_id":             bson.M{"$ne": matches[0].ID},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check category name: %w", err)
+		}
+		if count > 0 {
+			return &ConflictError{Resource: "category", Field: "name", Value: update.Name}
+		}
+		if err := s.moveCategory(ctx, matches[0], parent); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.repo.UpdateFieldsByQuery(ctx, filterDoc, setDoc)
+	if errors.Is(err, mongodb.ErrDuplicateKey) {
+		return &ConflictError{Resource: "category", Field: "name", Value: update.Name}
+	}
+	return err
 }
 
 func (s *categoryService) LoadAll(ctx context.Context) ([]entity.Category, error) {
-	posts, err := s.repo.LoadAll(ctx)
+	categories, err := s.repo.LoadAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load all categories: %w", err)
+	}
+	if err := s.attachBreadcrumbs(ctx, categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// Delete soft-deletes a leaf category. While posts still reference it the
+// delete is refused with ErrCategoryInUse, unless cascade is set, in which case
+// the category is first pulled from every post. It returns how many posts were detached.
+func (s *categoryService) Delete(ctx context.Context, id string, cascade bool) (int64, error) {
+	category, err := s.findCategoryByHex(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load all posts: %w", err)
+		return 0, err
+	}
+	oid := category.ID
+
+	children, err := s.repo.FindByFilter(ctx, bson.M{"parent_id": oid, "status": bson.M{"$ne": constant.CategoryStatusDeleted}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find sub-categories: %w", err)
+	}
+	if len(children) > 0 {
+		return 0, fmt.Errorf("%w: %d sub-category(ies)", ErrCategoryHasChildren, len(children))
+	}
+
+	referencing := bson.M{"categories": oid}
+	var detached int64
+	if cascade {
+		detached, err = s.blogRepo.UpdateManyByQuery(ctx, referencing, bson.M{
+			"$pull": bson.M{"categories": oid},
+			"$set":  bson.M{"updated_at": time.Now()},
+			// A missing version reads as 0, so $inc leaves legacy posts at 1 like Merge does.
+			"$inc": bson.M{"version": 1},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to detach category from posts: %w", err)
+		}
+	} else {
+		count, err := s.blogRepo.CountByQuery(ctx, referencing)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count posts in category: %w", err)
+		}
+		if count > 0 {
+			return 0, fmt.Errorf("%w: %d post(s)", ErrCategoryInUse, count)
+		}
+	}
+
+	if err := s.setStatus(ctx, oid, constant.CategoryStatusDeleted, nil); err != nil {
+		return detached, fmt.Errorf("failed to delete category: %w", err)
+	}
+	return detached, nil
+}
+
+// Archive keeps a category and its posts readable but stops new posts from
+// being filed under it.
+func (s *categoryService) Archive(ctx context.Context, id string) error {
+	category, err := s.findCategoryByHex(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.setStatus(ctx, category.ID, constant.CategoryStatusArchived, nil)
+}
+
+// Unarchive reopens an archived category.
+func (s *categoryService) Unarchive(ctx context.Context, id string) error {
+	category, err := s.findCategoryByHex(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.setStatus(ctx, category.ID, constant.CategoryStatusActive, nil)
+}
+
+// Merge folds the source category into the target: every post filed under the
+// source is re-pointed to the target in a single update, the source's
+// sub-categories move under the target, and the source is soft-deleted. It
+// returns how many posts were updated.
+func (s *categoryService) Merge(ctx context.Context, sourceID, targetID string) (int64, error) {
+	source, err := s.findCategoryByHex(ctx, sourceID)
+	if err != nil {
+		return 0, err
+	}
+	target, err := s.findCategoryByHex(ctx, targetID)
+	if err != nil {
+		return 0, err
+	}
+	if source.ID == target.ID {
+		return 0, fmt.Errorf("cannot merge a category into itself")
+	}
+	if target.Status == constant.CategoryStatusArchived {
+		return 0, fmt.Errorf("%w: %s", ErrCategoryArchived, target.ID.Hex())
+	}
+	if constant.IsValid(source.ID, target.Ancestors) {
+		return 0, fmt.Errorf("%w: cannot merge %s into its own sub-category", ErrCategoryCycle, source.ID.Hex())
+	}
+
+	// Swap the source for the target in place and drop the duplicate if a post
+	// already had both, so the category order editors chose is preserved.
+	replaced := bson.M{"$map": bson.M{
+		"input": "$categories",
+		"in":    bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$$this", source.ID}}, target.ID, "$$this"}},
+	}}
+	deduplicated := bson.M{"$reduce": bson.M{
+		"input":        replaced,
+		"initialValue": bson.A{},
+		"in": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{"$$this", "$$value"}},
+			"$$value",
+			bson.M{"$concatArrays": bson.A{"$$value", bson.A{"$$this"}}},
+		}},
+	}}
+	pipeline := bson.A{bson.M{"$set": bson.M{
+		"categories": deduplicated,
+		"updated_at": time.Now(),
+		"version":    bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1}},
+	}}}
+	updated, err := s.blogRepo.UpdateManyByQuery(ctx, bson.M{"categories": source.ID}, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("failed to re-point posts: %w", err)
+	}
+
+	children, err := s.repo.FindByFilter(ctx, bson.M{"parent_id": source.ID, "status": bson.M{"$ne": constant.CategoryStatusDeleted}})
+	if err != nil {
+		return updated, fmt.Errorf("failed to find sub-categories: %w", err)
+	}
+	for _, child := range children {
+		if err := s.moveCategory(ctx, child, &target.ID); err != nil {
+			return updated, err
+		}
+	}
+
+	if err := s.setStatus(ctx, source.ID, constant.CategoryStatusDeleted, &target.ID); err != nil {
+		return updated, fmt.Errorf("failed to delete merged category: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *categoryService) findCategoryByHex(ctx context.Context, id string) (*entity.Category, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert id to ObjectID: %w", err)
+	}
+
+	category, err := s.repo.FindByID(ctx, oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find category: %w", err)
+	}
+	if category == nil || category.Status == constant.CategoryStatusDeleted {
+		return nil, ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+func (s *categoryService) setStatus(ctx context.Context, id primitive.ObjectID, status constant.CategoryStatus, mergedInto *primitive.ObjectID) error {
+	setDoc := bson.M{
+		"status":     status,
+		"updated_at": time.Now(),
+	}
+	if status == constant.CategoryStatusDeleted {
+		// Release the name so a new category can take it.
+		setDoc["normalized_name"] = nil
+	}
+	if mergedInto != nil {
+		setDoc["merged_into"] = mergedInto
+	}
+	_, err := s.repo.UpdateFieldsByQuery(ctx, bson.M{"_id": id}, setDoc)
+	return err
+}
+
+// notDeletedFilter hides soft-deleted categories. Categories created before
+// the status field existed have none and are treated as active.
+var notDeletedFilter = query.Filter{
+	Field:    "status",
+	Operator: query.OpNotEqual,
+	Value:    constant.CategoryStatusDeleted,
+}
+
+func validateAccess(access []constant.Role) error {
+	for _, role := range access {
+		if !constant.IsValidRole(role) {
+			return fmt.Errorf("%w: %s", ErrInvalidAccessRole, role)
+		}
 	}
-	return posts, nil
+	return nil
 }
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// categoryPosts is a BlogPostRepository in which a fixed number of posts are
+// filed under whichever category is asked about. It keeps the bulk updates.
+type categoryPosts struct {
+	repositories.BlogPostRepository
+	filed   int64
+	updates []interface{}
+}
+
+func (r *categoryPosts) CountByQuery(ctx context.Context, filter bson.M) (int64, error) {
+	return r.filed, nil
+}
+
+func (r *categoryPosts) UpdateManyByQuery(ctx context.Context, filter bson.M, update interface{}) (int64, error) {
+	r.updates = append(r.updates, update)
+	return r.filed, nil
+}
+
+func TestDeleteCategory(t *testing.T) {
+	root, a, b, c, x := categoryTree()
+	removed := x
+	removed.Status = constant.CategoryStatusDeleted
+	removed.ParentID = &c.ID
+
+	tests := []struct {
+		name     string
+		delete   entity.Category
+		filed    int64
+		cascade  bool
+		wantErr  error
+		detached int64
+	}{
+		{name: "already deleted", delete: removed, wantErr: ErrCategoryNotFound},
+		// c's only child is deleted, so c counts as a leaf.
+		{name: "unused leaf", delete: c},
+		{name: "leaf with posts", delete: c, filed: 2, wantErr: ErrCategoryInUse},
+		{name: "cascade detaches posts", delete: c, filed: 2, cascade: true, detached: 2},
+		{name: "category with children", delete: b, wantErr: ErrCategoryHasChildren},
+		{name: "children block a cascade too", delete: a, filed: 2, cascade: true, wantErr: ErrCategoryHasChildren},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newMemoryCategories(t, root, a, b, c, removed)
+			posts := &categoryPosts{filed: tc.filed}
+			s := &categoryService{repo: repo, blogRepo: posts}
+
+			detached, err := s.Delete(context.Background(), tc.delete.ID.Hex(), tc.cascade)
+			status := repo.docs[tc.delete.ID]["status"]
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tc.wantErr)
+				}
+				if status != string(tc.delete.Status) {
+					t.Errorf("status = %v after a refused delete, want %s", status, tc.delete.Status)
+				}
+				if len(posts.updates) > 0 {
+					t.Errorf("posts were updated by a refused delete: %v", posts.updates)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if detached != tc.detached {
+				t.Errorf("detached = %d, want %d", detached, tc.detached)
+			}
+			if status != string(constant.CategoryStatusDeleted) {
+				t.Errorf("status = %v, want deleted", status)
+			}
+			if name, ok := repo.docs[tc.delete.ID]["normalized_name"]; !ok || name != nil {
+				t.Errorf("normalized_name = %v, want it released", name)
+			}
+
+			if !tc.cascade {
+				return
+			}
+			update := posts.updates[0].(bson.M)
+			if update["$pull"] == nil || update["$inc"] == nil {
+				t.Errorf("cascade update %v should $pull the category and $inc the version", update)
+			}
+		})
+	}
+}
+
+func TestMergeCategory(t *testing.T) {
+	root, a, b, c, x := categoryTree()
+	archived := x
+	archived.Status = constant.CategoryStatusArchived
+
+	tests := []struct {
+		name           string
+		source, target entity.Category
+		wantErr        error
+	}{
+		{name: "into itself", source: a, target: a},
+		{name: "into an archived category", source: c, target: archived, wantErr: ErrCategoryArchived},
+		{name: "into its own sub-category", source: a, target: c, wantErr: ErrCategoryCycle},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newMemoryCategories(t, root, a, b, c, archived)
+			posts := &categoryPosts{filed: 1}
+			s := &categoryService{repo: repo, blogRepo: posts}
+
+			_, err := s.Merge(context.Background(), tc.source.ID.Hex(), tc.target.ID.Hex())
+			if err == nil || (tc.wantErr != nil && !errors.Is(err, tc.wantErr)) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if len(posts.updates) > 0 {
+				t.Errorf("posts were updated by a refused merge: %v", posts.updates)
+			}
+		})
+	}
+
+	t.Run("moves children and posts", func(t *testing.T) {
+		repo := newMemoryCategories(t, root, a, b, c, x)
+		posts := &categoryPosts{filed: 3}
+		s := &categoryService{repo: repo, blogRepo: posts}
+
+		updated, err := s.Merge(context.Background(), a.ID.Hex(), x.ID.Hex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated != 3 || len(posts.updates) != 1 {
+			t.Errorf("updated %d posts in %d updates, want 3 in one", updated, len(posts.updates))
+		}
+		source := repo.docs[a.ID]
+		if source["status"] != string(constant.CategoryStatusDeleted) || source["merged_into"] != x.ID {
+			t.Errorf("source has status %v and merged_into %v, want deleted into %v", source["status"], source["merged_into"], x.ID)
+		}
+		child, _ := repo.FindByID(context.Background(), b.ID)
+		if child.ParentID == nil || *child.ParentID != x.ID {
+			t.Errorf("child parent = %v, want %v", child.ParentID, x.ID)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/domain/response"
+	"github.com/capigiba/capiary/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tree returns every category nested under its parent, roots first and
+// siblings ordered by name.
+func (s *categoryService) Tree(ctx context.Context) ([]*response.CategoryNode, error) {
+	categories, err := s.repo.FindByFilter(ctx, bson.M{"status": bson.M{"$ne": constant.CategoryStatusDeleted}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load categories: %w", err)
+	}
+	if err := s.attachBreadcrumbs(ctx, categories); err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[primitive.ObjectID]*response.CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &response.CategoryNode{Category: category, Children: []*response.CategoryNode{}}
+	}
+
+	roots := []*response.CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		// Roots, and categories whose parent vanished, are listed at the top.
+		roots = append(roots, node)
+	}
+
+	sortNodes(roots)
+	return roots, nil
+}
+
+func sortNodes(nodes []*response.CategoryNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+}
+
+// attachBreadcrumbs fills in the path from the root down to each category,
+// loading the names of all ancestors in one query.
+func (s *categoryService) attachBreadcrumbs(ctx context.Context, categories []entity.Category) error {
+	names := make(map[primitive.ObjectID]string, len(categories))
+	var missing []primitive.ObjectID
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+	for _, category := range categories {
+		for _, id := range category.Ancestors {
+			if _, ok := names[id]; !ok {
+				names[id] = ""
+				missing = append(missing, id)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		ancestors, err := s.repo.FindByFilter(ctx, bson.M{"_id": bson.M{"$in": missing}})
+		if err != nil {
+			return fmt.Errorf("failed to load ancestor categories: %w", err)
+		}
+		for _, ancestor := range ancestors {
+			names[ancestor.ID] = ancestor.Name
+		}
+	}
+
+	for i, category := range categories {
+		crumbs := make([]entity.Breadcrumb, 0, len(category.Ancestors)+1)
+		for _, id := range category.Ancestors {
+			crumbs = append(crumbs, entity.Breadcrumb{ID: id, Name: names[id]})
+		}
+		categories[i].Breadcrumbs = append(crumbs, entity.Breadcrumb{ID: category.ID, Name: category.Name})
+	}
+	return nil
+}
+
+// ancestorsUnder returns the materialized path for a child of parentID, which
+// is empty for a root category.
+func (s *categoryService) ancestorsUnder(ctx context.Context, parentID *primitive.ObjectID) ([]primitive.ObjectID, error) {
+	if parentID == nil {
+		return []primitive.ObjectID{}, nil
+	}
+	parent, err := s.repo.FindByID(ctx, *parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find parent category: %w", err)
+	}
+	if parent == nil || parent.Status == constant.CategoryStatusDeleted {
+		return nil, fmt.Errorf("%w: parent %s", ErrCategoryNotFound, parentID.Hex())
+	}
+	return append(append([]primitive.ObjectID{}, parent.Ancestors...), parent.ID), nil
+}
+
+// moveCategory re-parents a category and rewrites the path of its whole
+// subtree. Moving a category under itself or one of its descendants is
+// rejected with ErrCategoryCycle.
+func (s *categoryService) moveCategory(ctx context.Context, category entity.Category, parentID *primitive.ObjectID) error {
+	if parentID != nil && *parentID == category.ID {
+		return fmt.Errorf("%w: a category cannot be its own parent", ErrCategoryCycle)
+	}
+	ancestors, err := s.ancestorsUnder(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ancestors {
+		if id == category.ID {
+			return fmt.Errorf("%w: %s is a descendant of %s", ErrCategoryCycle, parentID.Hex(), category.ID.Hex())
+		}
+	}
+
+	now := time.Now()
+	if _, err := s.repo.UpdateFieldsByQuery(ctx, bson.M{"_id": category.ID}, bson.M{
+		"parent_id":  parentID,
+		"ancestors":  ancestors,
+		"updated_at": now,
+	}); err != nil {
+		return fmt.Errorf("failed to move category: %w", err)
+	}
+
+	descendants, err := s.repo.FindByFilter(ctx, bson.M{"ancestors": category.ID})
+	if err != nil {
+		return fmt.Errorf("failed to find sub-categories: %w", err)
+	}
+	for _, descendant := range descendants {
+		// Keep the part of the path below the moved category, replace the rest.
+		var below []primitive.ObjectID
+		for i, id := range descendant.Ancestors {
+			if id == category.ID {
+				below = descendant.Ancestors[i:]
+				break
+			}
+		}
+		path := append(append([]primitive.ObjectID{}, ancestors...), below...)
+		if _, err := s.repo.UpdateFieldsByQuery(ctx, bson.M{"_id": descendant.ID}, bson.M{
+			"ancestors":  path,
+			"updated_at": now,
+		}); err != nil {
+			return fmt.Errorf("failed to update sub-category %s: %w", descendant.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+// descendantIDs returns the given category followed by every category below it.
+func descendantIDs(ctx context.Context, repo repositories.CategoryRepository, id primitive.ObjectID) ([]primitive.ObjectID, error) {
+	descendants, err := repo.FindByFilter(ctx, bson.M{"ancestors": id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sub-categories: %w", err)
+	}
+	ids := make([]primitive.ObjectID, 0, len(descendants)+1)
+	ids = append(ids, id)
+	for _, descendant := range descendants {
+		ids = append(ids, descendant.ID)
+	}
+	return ids, nil
+}
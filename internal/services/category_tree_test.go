@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryCategories is a CategoryRepository over BSON documents in memory, so
+// tests see exactly what a write would store. Filters support equality, which
+// on an array field matches any element, and $ne.
+type memoryCategories struct {
+	repositories.CategoryRepository
+	docs map[primitive.ObjectID]bson.M
+}
+
+func newMemoryCategories(t *testing.T, categories ...entity.Category) *memoryCategories {
+	t.Helper()
+	r := &memoryCategories{docs: map[primitive.ObjectID]bson.M{}}
+	for _, c := range categories {
+		r.docs[c.ID] = toDoc(t, c)
+	}
+	return r
+}
+
+func toDoc(t *testing.T, v interface{}) bson.M {
+	t.Helper()
+	doc, err := roundTrip(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// roundTrip stores v as BSON and reads it back, so its values have the types
+// a document read from the database would.
+func roundTrip(v interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	err = bson.Unmarshal(raw, &doc)
+	return doc, err
+}
+
+func (r *memoryCategories) matches(doc, filter bson.M) bool {
+	filter, err := roundTrip(filter)
+	if err != nil {
+		panic(err)
+	}
+	for field, want := range filter {
+		if ops, isOp := want.(bson.M); isOp {
+			for op, value := range ops {
+				if op != "$ne" {
+					panic("memoryCategories: only $ne is supported, got " + op)
+				}
+				if r.equal(doc[field], value) {
+					return false
+				}
+			}
+		} else if !r.equal(doc[field], want) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *memoryCategories) equal(got, want interface{}) bool {
+	if list, ok := got.(primitive.A); ok {
+		for _, e := range list {
+			if e == want {
+				return true
+			}
+		}
+		return false
+	}
+	return got == want
+}
+
+func (r *memoryCategories) category(doc bson.M) entity.Category {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		panic(err)
+	}
+	var c entity.Category
+	if err := bson.Unmarshal(raw, &c); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (r *memoryCategories) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Category, error) {
+	doc, ok := r.docs[id]
+	if !ok {
+		return nil, nil
+	}
+	c := r.category(doc)
+	return &c, nil
+}
+
+func (r *memoryCategories) FindByFilter(ctx context.Context, filter bson.M) ([]entity.Category, error) {
+	var out []entity.Category
+	for _, doc := range r.docs {
+		if r.matches(doc, filter) {
+			out = append(out, r.category(doc))
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryCategories) UpdateFieldsByQuery(ctx context.Context, filter bson.M, fields bson.M) (int64, error) {
+	set, err := roundTrip(fields)
+	if err != nil {
+		return 0, err
+	}
+	var matched int64
+	for _, doc := range r.docs {
+		if r.matches(doc, filter) {
+			matched++
+			for k, v := range set {
+				doc[k] = v
+			}
+		}
+	}
+	return matched, nil
+}
+
+// categoryTree builds root > a > b > c, plus a separate root x.
+func categoryTree() (root, a, b, c, x entity.Category) {
+	node := func(name string, parent *entity.Category) entity.Category {
+		n := entity.Category{ID: primitive.NewObjectID(), Name: name, Status: constant.CategoryStatusActive, Ancestors: []primitive.ObjectID{}}
+		if parent != nil {
+			n.ParentID = &parent.ID
+			n.Ancestors = append(append([]primitive.ObjectID{}, parent.Ancestors...), parent.ID)
+		}
+		return n
+	}
+	root = node("root", nil)
+	a = node("a", &root)
+	b = node("b", &a)
+	c = node("c", &b)
+	x = node("x", nil)
+	return
+}
+
+func TestMoveCategoryToRoot(t *testing.T) {
+	root, a, b, c, x := categoryTree()
+	repo := newMemoryCategories(t, root, a, b, c, x)
+	s := &categoryService{repo: repo}
+
+	if err := s.moveCategory(context.Background(), b, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// parent_id is stored as null, which the category validator must accept.
+	if v, ok := repo.docs[b.ID]["parent_id"]; !ok || v != nil {
+		t.Errorf("parent_id = %v (present %v), want null", v, ok)
+	}
+	moved, _ := repo.FindByID(context.Background(), b.ID)
+	if moved.ParentID != nil || len(moved.Ancestors) != 0 {
+		t.Errorf("moved category has parent %v and ancestors %v, want a root", moved.ParentID, moved.Ancestors)
+	}
+	child, _ := repo.FindByID(context.Background(), c.ID)
+	if want := []primitive.ObjectID{b.ID}; !reflect.DeepEqual(child.Ancestors, want) {
+		t.Errorf("descendant ancestors = %v, want %v", child.Ancestors, want)
+	}
+}
+
+func TestMoveCategory(t *testing.T) {
+	root, a, b, c, x := categoryTree()
+
+	tests := []struct {
+		name    string
+		move    entity.Category
+		parent  *entity.Category
+		wantErr error
+		// Expected ancestors after the move, by category.
+		want map[primitive.ObjectID][]primitive.ObjectID
+	}{
+		{
+			name: "subtree under another root", move: a, parent: &x,
+			want: map[primitive.ObjectID][]primitive.ObjectID{
+				a.ID: {x.ID},
+				b.ID: {x.ID, a.ID},
+				c.ID: {x.ID, a.ID, b.ID},
+				x.ID: {},
+			},
+		},
+		{
+			name: "leaf up a level", move: c, parent: &a,
+			want: map[primitive.ObjectID][]primitive.ObjectID{
+				c.ID: {root.ID, a.ID},
+				b.ID: {root.ID, a.ID},
+			},
+		},
+		{name: "under itself", move: b, parent: &b, wantErr: ErrCategoryCycle},
+		{name: "under its child", move: a, parent: &b, wantErr: ErrCategoryCycle},
+		{name: "under a deeper descendant", move: root, parent: &c, wantErr: ErrCategoryCycle},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newMemoryCategories(t, root, a, b, c, x)
+			s := &categoryService{repo: repo}
+
+			err := s.moveCategory(context.Background(), tc.move, &tc.parent.ID)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tc.wantErr)
+				}
+				for _, n := range []entity.Category{root, a, b, c, x} {
+					got, _ := repo.FindByID(context.Background(), n.ID)
+					if !reflect.DeepEqual(got.Ancestors, n.Ancestors) {
+						t.Errorf("%s changed by a rejected move: %v", n.Name, got.Ancestors)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for id, want := range tc.want {
+				got, _ := repo.FindByID(context.Background(), id)
+				if !reflect.DeepEqual(got.Ancestors, want) {
+					t.Errorf("%s ancestors = %v, want %v", got.Name, got.Ancestors, want)
+				}
+			}
+			moved, _ := repo.FindByID(context.Background(), tc.move.ID)
+			if moved.ParentID == nil || *moved.ParentID != tc.parent.ID {
+				t.Errorf("parent = %v, want %v", moved.ParentID, tc.parent.ID)
+			}
+		})
+	}
+}
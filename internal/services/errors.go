@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrPostNotFound is returned when no blog post matches the given identifier.
+	ErrPostNotFound = errors.New("blog post not found")
+	// ErrRevisionNotFound is returned when no revision of the post matches the given identifier.
+	ErrRevisionNotFound = errors.New("blog revision not found")
+	// ErrCategoryNotFound is returned when a referenced category does not exist.
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrCategoryInUse is returned when deleting a category that posts still reference.
+	ErrCategoryInUse = errors.New("category is still referenced by posts")
+	// ErrCategoryHasChildren is returned when deleting a category that still has sub-categories.
+	ErrCategoryHasChildren = errors.New("category still has sub-categories")
+	// ErrCategoryArchived is returned when assigning posts to an archived category.
+	ErrCategoryArchived = errors.New("category is archived")
+	// ErrCategoryCycle is returned when a category would become its own ancestor.
+	ErrCategoryCycle = errors.New("category hierarchy cycle")
+	// ErrInvalidAccessRole is returned when a category's access list names an unknown role.
+	ErrInvalidAccessRole = errors.New("invalid role in access list")
+	// ErrForbidden is returned when the current user may not act on the post.
+	ErrForbidden = errors.New("not allowed to modify this blog post")
+	// ErrReadForbidden is returned when the current user's role may not read a category, or a post filed under one.
+	ErrReadForbidden = errors.New("not allowed to read this content")
+	// ErrInvalidStatus is returned when a requested blog status is unknown.
+	ErrInvalidStatus = errors.New("invalid blog status")
+	// ErrInvalidStatusTransition is returned when the workflow forbids moving a post to the requested status.
+	ErrInvalidStatusTransition = errors.New("status transition not allowed")
+	// ErrInvalidOperation is returned when a patch operation does not fit the post's current blocks.
+	ErrInvalidOperation = errors.New("invalid patch operation")
+	// ErrVersionConflict is returned when a post changed since the caller last read it.
+	ErrVersionConflict = errors.New("blog post version conflict")
+	// ErrEmptySearchQuery is returned when a search query contains no searchable words.
+	ErrEmptySearchQuery = errors.New("search query must contain at least one word")
+	// ErrInvalidPublishAt is returned when a post is scheduled without a future publish time.
+	ErrInvalidPublishAt = errors.New("publish_at must be set to a future time")
+	// ErrUserNotFound is returned when no user matches the given identifier.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrInvalidRole is returned when a requested role is unknown.
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrInvalidAccountTransition is returned when an account cannot move to the requested status from its current one.
+	ErrInvalidAccountTransition = errors.New("account status transition not allowed")
+	// ErrSelfModeration is returned when an admin tries to moderate, re-role or delete their own account.
+	ErrSelfModeration = errors.New("admins cannot moderate their own account")
+	// ErrReasonRequired is returned when suspending or banning a user without a reason.
+	ErrReasonRequired = errors.New("a reason is required")
+	// ErrAmbiguousFilter is returned when a single-post write is addressed by a filter matching several posts.
+	ErrAmbiguousFilter = errors.New("filter matches more than one blog post")
+	// ErrInvalidCursor is returned when a pagination cursor is malformed or was issued for another sort.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// ConflictError is returned when a write would duplicate a value that must be
+// unique, such as a category name.
+type ConflictError struct {
+	Resource string
+	Field    string
+	Value    string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s with %s %q already exists", e.Resource, e.Field, e.Value)
+}
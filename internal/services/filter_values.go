@@ -0,0 +1,40 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/capigiba/capiary/internal/infra/db/query"
+)
+
+// parseFilters parses raw filter parameters and checks them against the
+// entity's schema, which renames fields and types their values. Schema
+// violations come back as *query.FieldError.
+func parseFilters(schema *query.Schema, rawFilters []string) ([]query.Filter, error) {
+	parsed, err := query.ParseFilters(rawFilters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse filters: %w", err)
+	}
+	return schema.Filters(parsed)
+}
+
+// parseWhere parses a boolean "where" expression and checks its leaves against
+// the schema. An empty expression yields nil.
+func parseWhere(schema *query.Schema, rawWhere string) (*query.Condition, error) {
+	cond, err := query.ParseCondition(rawWhere)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Condition(cond)
+}
+
+func parseSorts(schema *query.Schema, rawSorts []string) ([]query.Sort, error) {
+	parsed, err := query.ParseSorts(rawSorts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sorts: %w", err)
+	}
+	return schema.Sorts(parsed)
+}
+
+func parseFields(schema *query.Schema, rawFields string) ([]string, error) {
+	return schema.Fields(query.ParseFields(rawFields))
+}
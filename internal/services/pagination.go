@@ -0,0 +1,87 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/capigiba/capiary/internal/infra/db/query"
+)
+
+// applyCursor switches a page-number query to keyset pagination when the
+// client sent a cursor: rows are selected relative to the cursor instead of
+// skipped, and read in reverse order when paging backwards. One extra row is
+// requested either way so pageResult can tell whether another page follows.
+// nulls is where the backend sorts null keys, e.g. query.NullsSmallest for MongoDB.
+func applyCursor(opts *query.QueryOptions, rawCursor string, nulls query.NullOrder) (*query.Cursor, error) {
+	opts.Limit++
+	if rawCursor == "" {
+		return nil, nil
+	}
+
+	cursor, err := query.DecodeCursor(rawCursor, opts.Sorts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	opts.Where = query.And(opts.Where, query.KeysetCondition(opts.Sorts, cursor, nulls))
+	opts.Skip = 0
+	if cursor.Before {
+		opts.Sorts = query.ReverseSorts(opts.Sorts)
+	}
+	return cursor, nil
+}
+
+// pageResult trims the extra row fetched by applyCursor, restores the order of
+// a backwards page and builds the cursors for the neighbouring pages. sorts is
+// the order the client asked for; hasPrevious marks page-number queries past
+// the first page. sortValues reads a row's sort key, e.g. query.MongoSortValues.
+func pageResult[T any](
+	items []T,
+	pageSize int,
+	cursor *query.Cursor,
+	sorts []query.Sort,
+	hasPrevious bool,
+	sortValues func(row interface{}, sorts []query.Sort) ([]interface{}, error),
+) ([]T, query.PageInfo, error) {
+	var info query.PageInfo
+	hasMore := len(items) > pageSize
+	if hasMore {
+		items = items[:pageSize]
+	}
+
+	before := cursor != nil && cursor.Before
+	if before {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	if len(items) == 0 {
+		return items, info, nil
+	}
+
+	// Moving forward there is a next page only if the extra row showed up, and
+	// a previous one whenever we did not start at the top; backwards, the reverse.
+	hasNext, hasPrev := hasMore, cursor != nil || hasPrevious
+	if before {
+		hasNext, hasPrev = true, hasMore
+	}
+	info.HasNext, info.HasPrev = hasNext, hasPrev
+
+	if hasNext {
+		values, err := sortValues(items[len(items)-1], sorts)
+		if err != nil {
+			return nil, info, err
+		}
+		if info.NextCursor, err = query.EncodeCursor(sorts, values, false); err != nil {
+			return nil, info, err
+		}
+	}
+	if hasPrev {
+		values, err := sortValues(items[0], sorts)
+		if err != nil {
+			return nil, info, err
+		}
+		if info.PrevCursor, err = query.EncodeCursor(sorts, values, true); err != nil {
+			return nil, info, err
+		}
+	}
+	return items, info, nil
+}
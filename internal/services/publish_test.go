@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// schedulePosts is a BlogPostRepository that only understands the claim
+// PublishDuePosts makes: the earliest scheduled post due by a given time.
+type schedulePosts struct {
+	repositories.BlogPostRepository
+	posts   []entity.BlogPost
+	claimed []string
+}
+
+func (r *schedulePosts) FindOneAndUpdateFields(ctx context.Context, filter bson.M, fields bson.M, sort bson.D) (*entity.BlogPost, error) {
+	due := filter["publish_at"].(bson.M)["$lte"].(time.Time)
+	var next *entity.BlogPost
+	for i := range r.posts {
+		p := &r.posts[i]
+		if p.Status != filter["status"] || p.PublishAt == nil || p.PublishAt.After(due) {
+			continue
+		}
+		if next == nil || p.PublishAt.Before(*next.PublishAt) {
+			next = p
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+	next.Status = fields["status"].(constant.BlogStatus)
+	publishedAt := fields["published_at"].(time.Time)
+	next.PublishedAt = &publishedAt
+	r.claimed = append(r.claimed, next.Title)
+	claimed := *next
+	return &claimed, nil
+}
+
+func TestPublishDuePosts(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		v := now.Add(d)
+		return &v
+	}
+	post := func(title string, status constant.BlogStatus, publishAt *time.Time) entity.BlogPost {
+		return entity.BlogPost{Title: title, Status: status, PublishAt: publishAt}
+	}
+
+	tests := []struct {
+		name  string
+		posts []entity.BlogPost
+		want  []string
+	}{
+		{"nothing scheduled", []entity.BlogPost{post("draft", constant.BlogStatusDraft, at(-time.Hour))}, nil},
+		{"due posts in publish order", []entity.BlogPost{
+			post("later", constant.BlogStatusScheduled, at(-time.Minute)),
+			post("earlier", constant.BlogStatusScheduled, at(-time.Hour)),
+		}, []string{"earlier", "later"}},
+		{"due exactly now", []entity.BlogPost{post("now", constant.BlogStatusScheduled, at(0))}, []string{"now"}},
+		{"future posts wait", []entity.BlogPost{
+			post("due", constant.BlogStatusScheduled, at(-time.Minute)),
+			post("future", constant.BlogStatusScheduled, at(time.Minute)),
+		}, []string{"due"}},
+		{"only scheduled posts", []entity.BlogPost{
+			post("draft", constant.BlogStatusDraft, at(-time.Hour)),
+			post("archived", constant.BlogStatusArchived, at(-time.Hour)),
+		}, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &schedulePosts{posts: tc.posts}
+			s := &blogPostService{repo: repo}
+
+			n, err := s.PublishDuePosts(context.Background(), now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != len(tc.want) || !reflect.DeepEqual(repo.claimed, tc.want) {
+				t.Errorf("published %d posts %v, want %v", n, repo.claimed, tc.want)
+			}
+			for _, p := range repo.posts {
+				if p.Status == constant.BlogStatusPublished && (p.PublishedAt == nil || !p.PublishedAt.Equal(now)) {
+					t.Errorf("post %q published at %v, want %v", p.Title, p.PublishedAt, now)
+				}
+			}
+		})
+	}
+}
+
+func TestPublishDuePostsStopsWhenCancelled(t *testing.T) {
+	now := time.Now()
+	repo := &schedulePosts{posts: []entity.BlogPost{{Title: "due", Status: constant.BlogStatusScheduled, PublishAt: &now}}}
+	s := &blogPostService{repo: repo}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n, err := s.PublishDuePosts(ctx, now)
+	if err == nil || n != 0 || len(repo.claimed) != 0 {
+		t.Errorf("published %d posts with err %v after cancel, want none and an error", n, err)
+	}
+}
@@ -3,10 +3,13 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/capigiba/capiary/internal/domain/constant"
 	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/infra/db/query"
 	"github.com/capigiba/capiary/internal/middleware"
 	"github.com/capigiba/capiary/internal/repositories"
 	"golang.org/x/crypto/bcrypt"
@@ -20,19 +23,29 @@ type UserService interface {
 	UpdateAvatar(ctx context.Context, userID uint64, avatarPath, avatarFolder string) error
 	GetUserByID(ctx context.Context, userID uint64) (*entity.User, error)
 	GetAllUsers(ctx context.Context) ([]entity.User, error)
-	DeleteUser(ctx context.Context, userID uint64) error
+	FindUsers(ctx context.Context, rawFilters []string, rawWhere string, rawSorts []string, rawFields string, page, pageSize int, cursor string, withTotal bool) ([]entity.User, query.PageInfo, error)
+	ModerateUser(ctx context.Context, actor *entity.User, userID uint64, action constant.UserAuditAction, reason string) (*entity.User, error)
+	ChangeUserRole(ctx context.Context, actor *entity.User, userID uint64, role constant.Role) (*entity.User, error)
+	DeleteUser(ctx context.Context, actor *entity.User, userID uint64, hard bool, reason string) error
+	ListUserAudit(ctx context.Context, userID uint64, page, pageSize int, cursor string, withTotal bool) ([]entity.UserAuditEntry, query.PageInfo, error)
 }
 
 type userService struct {
-	repo repositories.UserRepository
-	auth middleware.MiddlewareInterface
+	repo  repositories.UserRepository
+	audit repositories.UserAuditRepository
+	tx    repositories.Transactor
+	auth  middleware.MiddlewareInterface
 }
 
-// NewUserService returns a new user service.
-func NewUserService(repo repositories.UserRepository, auth middleware.MiddlewareInterface) UserService {
+// NewUserService returns a new user service. tx must share the Postgres
+// connection of repo and audit, so that account changes and their audit
+// entries commit together.
+func NewUserService(repo repositories.UserRepository, audit repositories.UserAuditRepository, tx repositories.Transactor, auth middleware.MiddlewareInterface) UserService {
 	return &userService{
-		repo: repo,
-		auth: auth,
+		repo:  repo,
+		audit: audit,
+		tx:    tx,
+		auth:  auth,
 	}
 }
 
@@ -105,10 +118,271 @@ func (s *userService) GetAllUsers(ctx context.Context) ([]entity.User, error) {
 	return s.repo.GetAllUsers(ctx)
 }
 
-// DeleteUser calls the repository to delete the user (soft or hard delete).
-func (s *userService) DeleteUser(ctx context.Context, userID uint64) error {
-	// If you want to do a soft-delete, you'd do:
-	return s.repo.SoftDeleteUser(ctx, userID)
-	// If you want to do a hard delete:
-	// return s.repo.DeleteUser(ctx, userID)
+// FindUsers lists users through the same filter/where/sort/fields/pagination
+// DSL as posts and categories. Password hashes are never returned.
+func (s *userService) FindUsers(
+	ctx context.Context,
+	rawFilters []string,
+	rawWhere string,
+	rawSorts []string,
+	rawFields string,
+	page, pageSize int,
+	cursor string,
+	withTotal bool,
+) ([]entity.User, query.PageInfo, error) {
+	parsedFilters, err := parseFilters(repositories.UserSchema, rawFilters)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+
+	where, err := parseWhere(repositories.UserSchema, rawWhere)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+
+	parsedSorts, err := parseSorts(repositories.UserSchema, rawSorts)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	if len(parsedSorts) == 0 {
+		parsedSorts = []query.Sort{{Field: "created_at", Desc: true}}
+	}
+	parsedSorts = append(parsedSorts, query.Sort{Field: "id", Desc: true})
+
+	parsedFields, err := parseFields(repositories.UserSchema, rawFields)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	parsedFields = query.WithSortFields(parsedFields, parsedSorts)
+
+	opts := query.QueryOptions{
+		Filters: parsedFilters,
+		Where:   where,
+		Sorts:   parsedSorts,
+		Fields:  parsedFields,
+		Skip:    int64((page - 1) * pageSize),
+		Limit:   int64(pageSize),
+	}
+
+	var total *int64
+	if withTotal {
+		n, err := s.repo.CountByQuery(ctx, opts)
+		if err != nil {
+			return nil, query.PageInfo{}, fmt.Errorf("failed to count users: %w", err)
+		}
+		total = &n
+	}
+
+	cur, err := applyCursor(&opts, cursor, query.NullsLargest)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+
+	users, err := s.repo.FindByQuery(ctx, opts)
+	if err != nil {
+		return nil, query.PageInfo{}, fmt.Errorf("failed to find users: %w", err)
+	}
+	users, info, err := pageResult(users, pageSize, cur, parsedSorts, page > 1, query.PostgresSortValues)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	info.Total = total
+
+	for i := range users {
+		users[i].Password = ""
+	}
+	return users, info, nil
+}
+
+// moderationTargets maps each moderation action to the status it leaves the account in.
+var moderationTargets = map[constant.UserAuditAction]constant.AccountStatus{
+	constant.UserAuditApprove:   constant.StatusActive,
+	constant.UserAuditReinstate: constant.StatusActive,
+	constant.UserAuditSuspend:   constant.StatusSuspended,
+	constant.UserAuditBan:       constant.StatusBanned,
+}
+
+// ModerateUser approves, suspends, bans or reinstates an account on behalf of
+// actor. Suspending and banning need a reason, which the user is shown when
+// they try to log in; approving and reinstating clear it. Approve only applies
+// to pending accounts and reinstate only to ones that were already approved.
+func (s *userService) ModerateUser(ctx context.Context, actor *entity.User, userID uint64, action constant.UserAuditAction, reason string) (*entity.User, error) {
+	to, ok := moderationTargets[action]
+	if !ok {
+		return nil, fmt.Errorf("unknown moderation action %q", action)
+	}
+	reason = strings.TrimSpace(reason)
+	if to != constant.StatusActive && reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	user, err := s.moderatedUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	from := user.Status
+	allowed := constant.CanTransitionAccountStatus(from, to)
+	if to == constant.StatusActive && (action == constant.UserAuditApprove) != (from == constant.StatusPending) {
+		allowed = false
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: cannot %s a %s account", ErrInvalidAccountTransition, action, from)
+	}
+
+	user.Status = to
+	user.StatusReason = ""
+	if to != constant.StatusActive {
+		user.StatusReason = reason
+	}
+	user.UpdatedAt = time.Now()
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateUser(ctx, user); err != nil {
+			return fmt.Errorf("failed to update user %d: %w", userID, err)
+		}
+		return s.record(ctx, actor, userID, action, string(from), string(to), reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// ChangeUserRole gives an account a new role on behalf of actor.
+func (s *userService) ChangeUserRole(ctx context.Context, actor *entity.User, userID uint64, role constant.Role) (*entity.User, error) {
+	if !constant.IsValidRole(role) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRole, role)
+	}
+
+	user, err := s.moderatedUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	from := user.Role
+	if from == role {
+		user.Password = ""
+		return user, nil
+	}
+
+	user.Role = role
+	user.UpdatedAt = time.Now()
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateUser(ctx, user); err != nil {
+			return fmt.Errorf("failed to update user %d: %w", userID, err)
+		}
+		return s.record(ctx, actor, userID, constant.UserAuditChangeRole, string(from), string(role), "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// DeleteUser removes an account on behalf of actor. A soft delete marks it
+// deleted and keeps the row, so the account can no longer log in but its
+// posts keep a known author; a hard delete removes the row for good. Either
+// way the audit log keeps the entry.
+func (s *userService) DeleteUser(ctx context.Context, actor *entity.User, userID uint64, hard bool, reason string) error {
+	user, err := s.moderatedUser(ctx, actor, userID)
+	if err != nil {
+		return err
+	}
+	reason = strings.TrimSpace(reason)
+
+	if hard {
+		return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := s.repo.DeleteUser(ctx, userID); err != nil {
+				return err
+			}
+			return s.record(ctx, actor, userID, constant.UserAuditHardDelete, string(user.Status), "", reason)
+		})
+	}
+
+	if user.Status == constant.StatusDeleted {
+		return fmt.Errorf("%w: user %d is already deleted", ErrInvalidAccountTransition, userID)
+	}
+	from := user.Status
+	user.Status = constant.StatusDeleted
+	user.StatusReason = reason
+	user.UpdatedAt = time.Now()
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateUser(ctx, user); err != nil {
+			return fmt.Errorf("failed to delete user %d: %w", userID, err)
+		}
+		return s.record(ctx, actor, userID, constant.UserAuditSoftDelete, string(from), string(constant.StatusDeleted), reason)
+	})
+}
+
+// ListUserAudit pages through the admin actions taken on a user, newest first.
+func (s *userService) ListUserAudit(ctx context.Context, userID uint64, page, pageSize int, cursor string, withTotal bool) ([]entity.UserAuditEntry, query.PageInfo, error) {
+	sorts := []query.Sort{{Field: "created_at", Desc: true}, {Field: "id", Desc: true}}
+	opts := query.QueryOptions{
+		Filters: []query.Filter{{Field: "target_id", Operator: query.OpEqual, Value: userID}},
+		Sorts:   sorts,
+		Skip:    int64((page - 1) * pageSize),
+		Limit:   int64(pageSize),
+	}
+
+	var total *int64
+	if withTotal {
+		n, err := s.audit.CountByQuery(ctx, opts)
+		if err != nil {
+			return nil, query.PageInfo{}, fmt.Errorf("failed to count audit entries: %w", err)
+		}
+		total = &n
+	}
+
+	cur, err := applyCursor(&opts, cursor, query.NullsLargest)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+
+	entries, err := s.audit.FindByQuery(ctx, opts)
+	if err != nil {
+		return nil, query.PageInfo{}, fmt.Errorf("failed to find audit entries: %w", err)
+	}
+	entries, info, err := pageResult(entries, pageSize, cur, sorts, page > 1, query.PostgresSortValues)
+	if err != nil {
+		return nil, query.PageInfo{}, err
+	}
+	info.Total = total
+	return entries, info, nil
+}
+
+// moderatedUser loads the account actor wants to act on. Admins may not act
+// on themselves, which also guarantees an active admin always remains.
+func (s *userService) moderatedUser(ctx context.Context, actor *entity.User, userID uint64) (*entity.User, error) {
+	if actor == nil {
+		return nil, errors.New("missing acting user")
+	}
+	if actor.ID == userID {
+		return nil, ErrSelfModeration
+	}
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user %d: %w", userID, err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// record appends an entry to the user audit log. Callers run it in the same
+// transaction as the change it describes, so a failure here undoes the change.
+func (s *userService) record(ctx context.Context, actor *entity.User, targetID uint64, action constant.UserAuditAction, from, to, reason string) error {
+	entry := &entity.UserAuditEntry{
+		ActorID:   actor.ID,
+		TargetID:  targetID,
+		Action:    action,
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	if err := s.audit.Add(ctx, entry); err != nil {
+		return fmt.Errorf("failed to audit %s of user %d: %w", action, targetID, err)
+	}
+	return nil
 }
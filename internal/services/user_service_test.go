@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/capigiba/capiary/internal/domain/constant"
+	"github.com/capigiba/capiary/internal/domain/entity"
+	"github.com/capigiba/capiary/internal/repositories"
+)
+
+// memoryUsers is a UserRepository over a map, counting the writes made to it.
+type memoryUsers struct {
+	repositories.UserRepository
+	users  map[uint64]entity.User
+	writes int
+}
+
+func (r *memoryUsers) GetUserByID(ctx context.Context, userID uint64) (*entity.User, error) {
+	user, ok := r.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func (r *memoryUsers) UpdateUser(ctx context.Context, user *entity.User) error {
+	r.users[user.ID] = *user
+	r.writes++
+	return nil
+}
+
+func (r *memoryUsers) DeleteUser(ctx context.Context, userID uint64) error {
+	delete(r.users, userID)
+	r.writes++
+	return nil
+}
+
+type memoryAudit struct {
+	repositories.UserAuditRepository
+	entries []entity.UserAuditEntry
+}
+
+func (r *memoryAudit) Add(ctx context.Context, entry *entity.UserAuditEntry) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+// inlineTx runs the unit of work without a transaction.
+type inlineTx struct{}
+
+func (inlineTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newModerationService(users ...entity.User) (*userService, *memoryUsers, *memoryAudit) {
+	repo := &memoryUsers{users: map[uint64]entity.User{}}
+	for _, u := range users {
+		repo.users[u.ID] = u
+	}
+	audit := &memoryAudit{}
+	return &userService{repo: repo, audit: audit, tx: inlineTx{}}, repo, audit
+}
+
+func TestAdminsCannotModerateThemselves(t *testing.T) {
+	admin := entity.User{ID: 1, Role: constant.RoleAdmin, Status: constant.StatusActive}
+	other := entity.User{ID: 2, Role: constant.RoleAdmin, Status: constant.StatusActive}
+	ctx := context.Background()
+
+	actions := []struct {
+		name string
+		run  func(s *userService, actor *entity.User, target uint64) error
+	}{
+		{"suspend", func(s *userService, actor *entity.User, target uint64) error {
+			_, err := s.ModerateUser(ctx, actor, target, constant.UserAuditSuspend, "spam")
+			return err
+		}},
+		{"ban", func(s *userService, actor *entity.User, target uint64) error {
+			_, err := s.ModerateUser(ctx, actor, target, constant.UserAuditBan, "spam")
+			return err
+		}},
+		{"change role", func(s *userService, actor *entity.User, target uint64) error {
+			_, err := s.ChangeUserRole(ctx, actor, target, constant.RoleBasic)
+			return err
+		}},
+		{"soft delete", func(s *userService, actor *entity.User, target uint64) error {
+			return s.DeleteUser(ctx, actor, target, false, "")
+		}},
+		{"hard delete", func(s *userService, actor *entity.User, target uint64) error {
+			return s.DeleteUser(ctx, actor, target, true, "")
+		}},
+	}
+	for _, action := range actions {
+		t.Run(action.name, func(t *testing.T) {
+			s, repo, audit := newModerationService(admin, other)
+			if err := action.run(s, &admin, admin.ID); !errors.Is(err, ErrSelfModeration) {
+				t.Errorf("acting on yourself: err = %v, want ErrSelfModeration", err)
+			}
+			if repo.writes != 0 || len(audit.entries) != 0 {
+				t.Errorf("a refused action made %d writes and %d audit entries", repo.writes, len(audit.entries))
+			}
+
+			if err := action.run(s, &admin, other.ID); err != nil {
+				t.Fatalf("acting on another admin: %v", err)
+			}
+			if len(audit.entries) != 1 || audit.entries[0].ActorID != admin.ID || audit.entries[0].TargetID != other.ID {
+				t.Errorf("audit entries = %+v, want one by %d on %d", audit.entries, admin.ID, other.ID)
+			}
+		})
+	}
+}
+
+func TestModerateUser(t *testing.T) {
+	admin := &entity.User{ID: 1, Role: constant.RoleAdmin, Status: constant.StatusActive}
+
+	tests := []struct {
+		name    string
+		from    constant.AccountStatus
+		action  constant.UserAuditAction
+		reason  string
+		want    constant.AccountStatus
+		wantErr error
+	}{
+		{name: "approve pending", from: constant.StatusPending, action: constant.UserAuditApprove, want: constant.StatusActive},
+		{name: "approve suspended", from: constant.StatusSuspended, action: constant.UserAuditApprove, wantErr: ErrInvalidAccountTransition},
+		{name: "reinstate suspended", from: constant.StatusSuspended, action: constant.UserAuditReinstate, want: constant.StatusActive},
+		{name: "reinstate pending", from: constant.StatusPending, action: constant.UserAuditReinstate, wantErr: ErrInvalidAccountTransition},
+		{name: "suspend active", from: constant.StatusActive, action: constant.UserAuditSuspend, reason: "spam", want: constant.StatusSuspended},
+		{name: "suspend without reason", from: constant.StatusActive, action: constant.UserAuditSuspend, reason: "  ", wantErr: ErrReasonRequired},
+		{name: "suspend banned", from: constant.StatusBanned, action: constant.UserAuditSuspend, reason: "spam", wantErr: ErrInvalidAccountTransition},
+		{name: "ban pending", from: constant.StatusPending, action: constant.UserAuditBan, reason: "bot", want: constant.StatusBanned},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, repo, audit := newModerationService(entity.User{ID: 2, Role: constant.RoleBasic, Status: tc.from})
+
+			user, err := s.ModerateUser(context.Background(), admin, 2, tc.action, tc.reason)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tc.wantErr)
+				}
+				if repo.users[2].Status != tc.from || len(audit.entries) != 0 {
+					t.Errorf("a refused action left status %s and %d audit entries", repo.users[2].Status, len(audit.entries))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user.Status != tc.want || repo.users[2].Status != tc.want {
+				t.Errorf("status = %s, stored %s, want %s", user.Status, repo.users[2].Status, tc.want)
+			}
+			entry := audit.entries[0]
+			if entry.Action != tc.action || entry.From != string(tc.from) || entry.To != string(tc.want) {
+				t.Errorf("audit entry = %+v, want %s from %s to %s", entry, tc.action, tc.from, tc.want)
+			}
+		})
+	}
+}
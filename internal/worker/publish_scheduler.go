@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/capigiba/capiary/internal/services"
+	"github.com/capigiba/capiary/pkg/logger"
+)
+
+const defaultPublishInterval = 30 * time.Second
+
+// PublishScheduler periodically publishes blog posts whose publish_at has passed.
+type PublishScheduler struct {
+	service  services.BlogPostService
+	interval time.Duration
+	log      logger.Logger
+}
+
+// NewPublishScheduler creates a PublishScheduler polling every interval.
+// A non-positive interval falls back to 30 seconds.
+func NewPublishScheduler(service services.BlogPostService, interval time.Duration) *PublishScheduler {
+	if interval <= 0 {
+		interval = defaultPublishInterval
+	}
+	return &PublishScheduler{
+		service:  service,
+		interval: interval,
+		log:      logger.NewLogger("publish-scheduler"),
+	}
+}
+
+// Run polls until ctx is cancelled. It is safe to run on every replica:
+// each due post is claimed atomically by exactly one of them.
+func (s *PublishScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.log.Infof("publish scheduler started, polling every %s", s.interval)
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.log.Info("publish scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *PublishScheduler) tick(ctx context.Context) {
+	published, err := s.service.PublishDuePosts(ctx, time.Now())
+	if err != nil && ctx.Err() == nil {
+		s.log.Errorf("failed to publish scheduled posts: %v", err)
+	}
+	if published > 0 {
+		s.log.Infof("published %d scheduled post(s)", published)
+	}
+}
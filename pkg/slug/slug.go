@@ -0,0 +1,25 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Make turns arbitrary text into a lowercase, URL-safe slug made of ASCII
+// letters, digits and single hyphens, e.g. "Hello, World!" -> "hello-world".
+func Make(text string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(text) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			pendingHyphen = false
+			continue
+		}
+		pendingHyphen = true
+	}
+	return b.String()
+}